@@ -25,7 +25,7 @@ func (p PayloadAttributes) MarshalJSON() ([]byte, error) {
 		BeaconRoot            *common.Hash        `json:"parentBeaconBlockRoot"`
 		BaseFeePerGas         *big.Int            `json:"baseFeePerGas" gencodec:"required"`
 		BlockMetadata         *BlockMetadata      `json:"blockMetadata" gencodec:"required"`
-		L1Origin              *rawdb.L1Origin     `json:"l1Origin"      gencodec:"required"`
+		L1Origin              *rawdb.L1Origin     `json:"l1Origin"`
 	}
 	var enc PayloadAttributes
 	enc.Timestamp = hexutil.Uint64(p.Timestamp)
@@ -49,7 +49,7 @@ func (p *PayloadAttributes) UnmarshalJSON(input []byte) error {
 		BeaconRoot            *common.Hash        `json:"parentBeaconBlockRoot"`
 		BaseFeePerGas         *big.Int            `json:"baseFeePerGas" gencodec:"required"`
 		BlockMetadata         *BlockMetadata      `json:"blockMetadata" gencodec:"required"`
-		L1Origin              *rawdb.L1Origin     `json:"l1Origin"      gencodec:"required"`
+		L1Origin              *rawdb.L1Origin     `json:"l1Origin"`
 	}
 	var dec PayloadAttributes
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -81,9 +81,8 @@ func (p *PayloadAttributes) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 'blockMetadata' for PayloadAttributes")
 	}
 	p.BlockMetadata = dec.BlockMetadata
-	if dec.L1Origin == nil {
-		return errors.New("missing required field 'l1Origin' for PayloadAttributes")
+	if dec.L1Origin != nil {
+		p.L1Origin = dec.L1Origin
 	}
-	p.L1Origin = dec.L1Origin
 	return nil
 }
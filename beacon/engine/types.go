@@ -53,7 +53,7 @@ type PayloadAttributes struct {
 	// CHANGE(taiko): extra fields.
 	BaseFeePerGas *big.Int        `json:"baseFeePerGas" gencodec:"required"`
 	BlockMetadata *BlockMetadata  `json:"blockMetadata" gencodec:"required"`
-	L1Origin      *rawdb.L1Origin `json:"l1Origin"      gencodec:"required"`
+	L1Origin      *rawdb.L1Origin `json:"l1Origin"` // nil for a preconfirmation block
 }
 
 // JSON type overrides for PayloadAttributes.
@@ -71,7 +71,8 @@ type BlockMetadata struct {
 	Timestamp   uint64         `json:"timestamp"    gencodec:"required"`
 	MixHash     common.Hash    `json:"mixHash"      gencodec:"required"`
 
-	// Extra fields required in taiko-geth.
+	// Extra fields required in taiko-geth, the TxList is the RLP encoded transactions
+	// list, not compressed with the txList codec.
 	TxList    []byte `json:"txList"          gencodec:"required"`
 	ExtraData []byte `json:"extraData"       gencodec:"required"`
 }
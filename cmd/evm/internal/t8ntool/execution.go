@@ -23,6 +23,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/misc"
@@ -68,6 +69,9 @@ type ExecutionResult struct {
 	CurrentBlobGasUsed   *math.HexOrDecimal64  `json:"blobGasUsed,omitempty"`
 	RequestsHash         *common.Hash          `json:"requestsRoot,omitempty"`
 	DepositRequests      *types.Deposits       `json:"depositRequests,omitempty"`
+	// CHANGE(taiko): the base fee paid to the treasury and to the coinbase.
+	TaikoBaseFeeToTreasury *math.HexOrDecimal256 `json:"baseFeeToTreasury,omitempty"`
+	TaikoBaseFeeToCoinbase *math.HexOrDecimal256 `json:"baseFeeToCoinbase,omitempty"`
 }
 
 type ommer struct {
@@ -97,6 +101,7 @@ type stEnv struct {
 	ParentExcessBlobGas   *uint64                             `json:"parentExcessBlobGas,omitempty"`
 	ParentBlobGasUsed     *uint64                             `json:"parentBlobGasUsed,omitempty"`
 	ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot"`
+	ExtraData             []byte                              `json:"currentExtraData,omitempty"` // CHANGE(taiko): the basefee sharing config of Ontake blocks
 }
 
 type stEnvMarshaling struct {
@@ -115,6 +120,7 @@ type stEnvMarshaling struct {
 	ExcessBlobGas       *math.HexOrDecimal64
 	ParentExcessBlobGas *math.HexOrDecimal64
 	ParentBlobGasUsed   *math.HexOrDecimal64
+	ExtraData           hexutil.Bytes
 }
 
 type rejectedTx struct {
@@ -151,6 +157,7 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		blobGasUsed = uint64(0)
 		receipts    = make(types.Receipts, 0)
 		txIndex     = 0
+		taikoFees   = newTaikoBaseFees() // CHANGE(taiko): base fee shares of the treasury and the coinbase
 	)
 	gaspool.AddGas(pre.Env.GasLimit)
 	vmContext := vm.BlockContext{
@@ -213,6 +220,14 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			rejectedTxs = append(rejectedTxs, &rejectedTx{i, err.Error()})
 			continue
 		}
+		// CHANGE(taiko): mark the first transaction as the anchor transaction.
+		if i == 0 && chainConfig.Taiko {
+			if err := tx.MarkAsAnchor(); err != nil {
+				log.Warn("rejected tx", "index", i, "hash", tx.Hash(), "error", err)
+				rejectedTxs = append(rejectedTxs, &rejectedTx{i, err.Error()})
+				continue
+			}
+		}
 		if tx.Type() == types.BlobTxType && vmContext.BlobBaseFee == nil {
 			errMsg := "blob tx used but field env.ExcessBlobGas missing"
 			log.Warn("rejected tx", "index", i, "hash", tx.Hash(), "error", errMsg)
@@ -225,6 +240,8 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			rejectedTxs = append(rejectedTxs, &rejectedTx{i, err.Error()})
 			continue
 		}
+		// CHANGE(taiko): decode the basefeeSharingPctg config from the extradata.
+		msg.BasefeeSharingPctg = chainConfig.TaikoParamsAt(vmContext.BlockNumber, vmContext.Time).BasefeeSharingPctg(pre.Env.ExtraData)
 		txBlobGas := uint64(0)
 		if tx.Type() == types.BlobTxType {
 			txBlobGas = uint64(params.BlobTxBlobGasPerBlob * len(tx.BlobHashes()))
@@ -277,6 +294,10 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		}
 		blobGasUsed += txBlobGas
 		gasUsed += msgResult.UsedGas
+		// CHANGE(taiko): sum up the base fee paid to the treasury and to the coinbase.
+		if chainConfig.Taiko {
+			taikoFees.add(tx, vmContext.BaseFee, msgResult.UsedGas, msg.BasefeeSharingPctg)
+		}
 
 		// Receipt:
 		{
@@ -371,6 +392,11 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		GasUsed:     (math.HexOrDecimal64)(gasUsed),
 		BaseFee:     (*math.HexOrDecimal256)(vmContext.BaseFee),
 	}
+	// CHANGE(taiko): report the base fee paid to the treasury and to the coinbase.
+	if chainConfig.Taiko {
+		execRs.TaikoBaseFeeToTreasury = (*math.HexOrDecimal256)(taikoFees.treasury)
+		execRs.TaikoBaseFeeToCoinbase = (*math.HexOrDecimal256)(taikoFees.coinbase)
+	}
 	if pre.Env.Withdrawals != nil {
 		h := types.DeriveSha(types.Withdrawals(pre.Env.Withdrawals), trie.NewStackTrie(nil))
 		execRs.WithdrawalsRoot = &h
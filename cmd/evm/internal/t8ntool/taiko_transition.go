@@ -0,0 +1,50 @@
+package t8ntool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// applyTaikoChecks requires the base fee of a Taiko block to be set in the env,
+// since it's computed by the TaikoL2 contract instead of from the parent block,
+// and to be no less than the minimum base fee of the active Taiko fork.
+func applyTaikoChecks(env *stEnv, chainConfig *params.ChainConfig) error {
+	if !chainConfig.Taiko {
+		return nil
+	}
+	if env.BaseFee == nil {
+		return NewError(ErrorConfig, errors.New("Taiko config but missing 'currentBaseFee' in env section"))
+	}
+	number := new(big.Int).SetUint64(env.Number)
+	if minBaseFee := chainConfig.TaikoParamsAt(number, env.Timestamp).MinBaseFee; minBaseFee != nil && env.BaseFee.Cmp(minBaseFee) < 0 {
+		return NewError(ErrorConfig, fmt.Errorf("base fee %v is less than the minimum base fee %v of the %v fork",
+			env.BaseFee, minBaseFee, chainConfig.TaikoForkAt(number, env.Timestamp)))
+	}
+	return nil
+}
+
+// taikoBaseFees sums up the base fee paid to the treasury and to the coinbase by the
+// transactions of a Taiko block, the anchor transaction pays no fees.
+type taikoBaseFees struct {
+	treasury *big.Int
+	coinbase *big.Int
+}
+
+func newTaikoBaseFees() *taikoBaseFees {
+	return &taikoBaseFees{treasury: new(big.Int), coinbase: new(big.Int)}
+}
+
+// add adds the base fee paid by the given transaction.
+func (f *taikoBaseFees) add(tx *types.Transaction, baseFee *big.Int, gasUsed uint64, sharingPctg uint8) {
+	if tx.IsAnchor() {
+		return
+	}
+	treasury, coinbase := core.SplitTaikoBaseFee(baseFee, gasUsed, sharingPctg)
+	f.treasury.Add(f.treasury, treasury)
+	f.coinbase.Add(f.coinbase, coinbase)
+}
@@ -184,6 +184,10 @@ func Transition(ctx *cli.Context) error {
 	if txIt, err = loadTransactions(txStr, inputData, chainConfig); err != nil {
 		return err
 	}
+	// CHANGE(taiko): the base fee of Taiko blocks is not derived from the parent.
+	if err := applyTaikoChecks(&prestate.Env, chainConfig); err != nil {
+		return err
+	}
 	if err := applyLondonChecks(&prestate.Env, chainConfig); err != nil {
 		return err
 	}
@@ -287,6 +287,30 @@ func TestT8n(t *testing.T) {
 			output: t8nOutput{alloc: true, result: true},
 			expOut: "exp.json",
 		},
+		{ // Taiko Ontake: anchor transaction and basefee sharing
+			base: "./testdata/taiko",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "TaikoOntake", "",
+			},
+			output: t8nOutput{alloc: true, result: true},
+			expOut: "exp.json",
+		},
+		{ // Taiko genesis fork: the treasury receives the whole base fee
+			base: "./testdata/taiko",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "TaikoGenesis", "",
+			},
+			output: t8nOutput{alloc: true, result: true},
+			expOut: "exp_genesis.json",
+		},
+		{ // Taiko Ontake: exit (3) on a base fee below the minimum
+			base: "./testdata/taiko",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.minbasefee.json", "TaikoOntake", "",
+			},
+			output:      t8nOutput{alloc: true, result: true},
+			expExitCode: 3,
+		},
 	} {
 		args := []string{"t8n"}
 		args = append(args, tc.output.get()...)
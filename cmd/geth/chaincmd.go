@@ -248,6 +248,10 @@ func dumpGenesis(ctx *cli.Context) error {
 		genesis = utils.MakeGenesis(ctx)
 	} else if ctx.IsSet(utils.DeveloperFlag.Name) && !ctx.IsSet(utils.DataDirFlag.Name) {
 		genesis = core.DeveloperGenesisBlock(11_500_000, nil)
+		// CHANGE(taiko): dump the Taiko developer genesis when `--taiko` flag is set.
+		if ctx.IsSet(utils.TaikoFlag.Name) {
+			genesis = core.TaikoDeveloperGenesisBlock(11_500_000, nil, utils.MakeTaikoDevPredeploys(ctx))
+		}
 	}
 
 	if genesis != nil {
@@ -233,7 +233,17 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 
 	if ctx.IsSet(utils.DeveloperFlag.Name) {
 		// Start dev mode.
-		simBeacon, err := catalyst.NewSimulatedBeacon(ctx.Uint64(utils.DeveloperPeriodFlag.Name), eth)
+		var (
+			simBeacon *catalyst.SimulatedBeacon
+			err       error
+		)
+		// CHANGE(taiko): seal the blocks with anchor transactions in the Taiko
+		// developer mode.
+		if ctx.IsSet(utils.TaikoFlag.Name) {
+			simBeacon, err = catalyst.NewTaikoSimulatedBeacon(ctx.Uint64(utils.DeveloperPeriodFlag.Name), eth, utils.MakeTaikoDevConfig(ctx))
+		} else {
+			simBeacon, err = catalyst.NewSimulatedBeacon(ctx.Uint64(utils.DeveloperPeriodFlag.Name), eth)
+		}
 		if err != nil {
 			utils.Fatalf("failed to register dev mode catalyst service: %v", err)
 		}
@@ -261,7 +261,7 @@ func init() {
 		metricsFlags,
 	)
 	// CHANGE(taiko): append Taiko flags into the original GETH flags
-	app.Flags = append(app.Flags, &utils.TaikoFlag)
+	app.Flags = append(app.Flags, &utils.TaikoFlag, &utils.TaikoPreconfSequencerFlag, &utils.TaikoPreconfSigningKeyFlag, &utils.TaikoDisableAPIsFlag, &utils.TxPoolTaikoMinBaseFeeFlag, &utils.TxPoolTaikoAllowZeroFeeCapFlag, &utils.TaikoDevBaseFeeFlag, &utils.TaikoDevBasefeeSharingPctgFlag, &utils.TaikoDevPredeploysFlag)
 
 	flags.AutoEnvVars(app.Flags, "GETH")
 
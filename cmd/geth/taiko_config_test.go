@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/txpool"
+)
+
+func TestLoadTaikoConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+	config := `
+[Eth]
+DisableTaikoAPIs = true
+
+[Eth.TxPool.Taiko]
+AllowZeroFeeCap = true
+
+[Eth.TxPool.Taiko.MinBaseFees]
+ontake = 0
+`
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var cfg gethConfig
+	if err := loadConfig(file, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Eth.DisableTaikoAPIs {
+		t.Fatal("expected the Taiko APIs to be disabled")
+	}
+	want := txpool.TaikoConfig{MinBaseFees: map[string]uint64{"ontake": 0}, AllowZeroFeeCap: true}
+	if !reflect.DeepEqual(cfg.Eth.TxPool.Taiko, want) {
+		t.Fatalf("expected %+v, got %+v", want, cfg.Eth.TxPool.Taiko)
+	}
+}
@@ -1537,6 +1537,8 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	// CHANGE(taiko): set the Taiko fee cap checks.
+	setTaikoTxPool(ctx, &cfg.Taiko)
 }
 
 func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
@@ -1656,6 +1658,8 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
 	setLes(ctx, cfg)
+	// CHANGE(taiko): set the preconfirmation block gossip options.
+	setTaikoPreconf(ctx, cfg)
 
 	// Cap the cache allowance and tune the garbage collector
 	mem, err := gopsutil.VirtualMemory()
@@ -1794,8 +1798,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	}
 	// Override any default configs for hard coded networks.
 	switch {
-	// CHANGE(taiko): when `--taiko` flag is set, use the Taiko genesis.
-	case ctx.IsSet(TaikoFlag.Name):
+	// CHANGE(taiko): when `--taiko` flag is set, use the Taiko genesis, unless in
+	// developer mode.
+	case ctx.IsSet(TaikoFlag.Name) && !ctx.Bool(DeveloperFlag.Name):
 		cfg.Genesis = core.TaikoGenesisBlock(cfg.NetworkId)
 	case ctx.Bool(MainnetFlag.Name):
 		if !ctx.IsSet(NetworkIdFlag.Name) {
@@ -1864,7 +1869,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		log.Info("Using developer account", "address", developer.Address)
 
 		// Create a new developer genesis block or reuse existing one
-		cfg.Genesis = core.DeveloperGenesisBlock(ctx.Uint64(DeveloperGasLimitFlag.Name), &developer.Address)
+		// CHANGE(taiko): use the Taiko developer chain when `--taiko` flag is set.
+		if ctx.IsSet(TaikoFlag.Name) {
+			cfg.Genesis = core.TaikoDeveloperGenesisBlock(ctx.Uint64(DeveloperGasLimitFlag.Name), &developer.Address, MakeTaikoDevPredeploys(ctx))
+		} else {
+			cfg.Genesis = core.DeveloperGenesisBlock(ctx.Uint64(DeveloperGasLimitFlag.Name), &developer.Address)
+		}
 		if ctx.IsSet(DataDirFlag.Name) {
 			chaindb := tryMakeReadOnlyDatabase(ctx, stack)
 			if rawdb.ReadCanonicalHash(chaindb, 0) != (common.Hash{}) {
@@ -1,10 +1,19 @@
 package utils
 
 import (
+	"math/big"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/catalyst"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -16,11 +25,133 @@ var (
 		Name:  "taiko",
 		Usage: "Taiko network",
 	}
+	TaikoPreconfSequencerFlag = cli.StringFlag{
+		Name:  "taiko.preconf.sequencer",
+		Usage: "Address of the sequencer whose gossiped preconfirmation blocks are accepted",
+	}
+	TaikoPreconfSigningKeyFlag = cli.StringFlag{
+		Name:  "taiko.preconf.signingkey",
+		Usage: "Sequencer private key file to sign and gossip the local preconfirmation blocks with",
+	}
+	TaikoDisableAPIsFlag = cli.BoolFlag{
+		Name:  "taiko.disableapis",
+		Usage: "Disable the taiko and taikoAuth RPC APIs",
+	}
+	TxPoolTaikoMinBaseFeeFlag = cli.StringFlag{
+		Name:  "txpool.taiko.minbasefee",
+		Usage: "Comma separated minimum fee caps (wei) of the Taiko forks, overriding the protocol minimum base fee, zero disables the check (e.g. ontake=0)",
+	}
+	TxPoolTaikoAllowZeroFeeCapFlag = cli.BoolFlag{
+		Name:  "txpool.taiko.allowzerofeecap",
+		Usage: "Accept transactions with a zero fee cap into the pool",
+	}
+	TaikoDevBaseFeeFlag = cli.Uint64Flag{
+		Name:     "taiko.dev.basefee",
+		Usage:    "Base fee (wei) of the blocks sealed in the Taiko developer mode",
+		Value:    catalyst.DefaultTaikoDevConfig().BaseFee.Uint64(),
+		Category: flags.DevCategory,
+	}
+	TaikoDevBasefeeSharingPctgFlag = cli.UintFlag{
+		Name:     "taiko.dev.basefeesharingpctg",
+		Usage:    "Percentage of the base fee paid to the coinbase in the Taiko developer mode",
+		Value:    uint(catalyst.DefaultTaikoDevConfig().BasefeeSharingPctg),
+		Category: flags.DevCategory,
+	}
+	TaikoDevPredeploysFlag = cli.StringFlag{
+		Name:     "taiko.dev.predeploys",
+		Usage:    "Genesis alloc JSON file of the protocol contracts (e.g. TaikoL2, SignalService) predeployed in the Taiko developer mode, generated by the protocol for the developer chain ID",
+		Category: flags.DevCategory,
+	}
 )
 
+// setTaikoTxPool applies the Taiko fee cap flags to the transaction pool config.
+func setTaikoTxPool(ctx *cli.Context, cfg *txpool.TaikoConfig) {
+	if ctx.IsSet(TxPoolTaikoMinBaseFeeFlag.Name) {
+		cfg.MinBaseFees = make(map[string]uint64)
+		for _, entry := range strings.Split(ctx.String(TxPoolTaikoMinBaseFeeFlag.Name), ",") {
+			name, fee, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				Fatalf("Option %q: invalid entry %q, want <fork>=<wei>", TxPoolTaikoMinBaseFeeFlag.Name, entry)
+			}
+			value, err := strconv.ParseUint(fee, 10, 64)
+			if err != nil {
+				Fatalf("Option %q: invalid fee of %s: %v", TxPoolTaikoMinBaseFeeFlag.Name, name, err)
+			}
+			cfg.MinBaseFees[strings.ToLower(name)] = value
+		}
+	}
+	if ctx.IsSet(TxPoolTaikoAllowZeroFeeCapFlag.Name) {
+		cfg.AllowZeroFeeCap = ctx.Bool(TxPoolTaikoAllowZeroFeeCapFlag.Name)
+	}
+	if err := cfg.Validate(); err != nil {
+		Fatalf("Invalid Taiko transaction pool config: %v", err)
+	}
+}
+
+// setTaikoPreconf applies the preconfirmation block gossip and Taiko API flags to
+// the config.
+func setTaikoPreconf(ctx *cli.Context, cfg *ethconfig.Config) {
+	if ctx.IsSet(TaikoPreconfSequencerFlag.Name) {
+		sequencer := ctx.String(TaikoPreconfSequencerFlag.Name)
+		if !common.IsHexAddress(sequencer) {
+			Fatalf("Option %q: invalid address %q", TaikoPreconfSequencerFlag.Name, sequencer)
+		}
+		cfg.PreconfSequencer = common.HexToAddress(sequencer)
+	}
+	if ctx.IsSet(TaikoPreconfSigningKeyFlag.Name) {
+		key, err := crypto.LoadECDSA(ctx.String(TaikoPreconfSigningKeyFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", TaikoPreconfSigningKeyFlag.Name, err)
+		}
+		// The sequencer itself only accepts the blocks signed by its own key.
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		if cfg.PreconfSequencer != (common.Address{}) && cfg.PreconfSequencer != addr {
+			Fatalf("Option %q: key of %s doesn't match the sequencer %s", TaikoPreconfSigningKeyFlag.Name, addr, cfg.PreconfSequencer)
+		}
+		cfg.PreconfSequencer = addr
+		cfg.PreconfSigningKey = key
+	}
+	if ctx.IsSet(TaikoDisableAPIsFlag.Name) {
+		cfg.DisableTaikoAPIs = ctx.Bool(TaikoDisableAPIsFlag.Name)
+	}
+}
+
+// MakeTaikoDevConfig creates the config of the blocks sealed in the Taiko developer
+// mode from the command line flags.
+func MakeTaikoDevConfig(ctx *cli.Context) *catalyst.TaikoDevConfig {
+	pctg := ctx.Uint(TaikoDevBasefeeSharingPctgFlag.Name)
+	if pctg > 100 {
+		Fatalf("Option %q: invalid percentage %d", TaikoDevBasefeeSharingPctgFlag.Name, pctg)
+	}
+	return &catalyst.TaikoDevConfig{
+		BaseFee:            new(big.Int).SetUint64(ctx.Uint64(TaikoDevBaseFeeFlag.Name)),
+		BasefeeSharingPctg: uint8(pctg),
+	}
+}
+
+// MakeTaikoDevPredeploys loads the protocol contracts predeployed in the genesis of
+// the Taiko developer mode, the TaikoL2 contract must be one of them.
+func MakeTaikoDevPredeploys(ctx *cli.Context) types.GenesisAlloc {
+	if !ctx.IsSet(TaikoDevPredeploysFlag.Name) {
+		return nil
+	}
+	blob, err := os.ReadFile(ctx.String(TaikoDevPredeploysFlag.Name))
+	if err != nil {
+		Fatalf("Option %q: %v", TaikoDevPredeploysFlag.Name, err)
+	}
+	var alloc types.GenesisAlloc
+	if err := alloc.UnmarshalJSON(blob); err != nil {
+		Fatalf("Option %q: invalid genesis alloc: %v", TaikoDevPredeploysFlag.Name, err)
+	}
+	if taikoL2 := params.TaikoDevChainConfig().TaikoL2ContractAddress(); len(alloc[taikoL2].Code) == 0 {
+		Fatalf("Option %q: no TaikoL2 contract at %s", TaikoDevPredeploysFlag.Name, taikoL2)
+	}
+	return alloc
+}
+
 // RegisterTaikoAPIs initializes and registers the Taiko RPC APIs.
 func RegisterTaikoAPIs(stack *node.Node, cfg *ethconfig.Config, backend *eth.Ethereum) {
-	if os.Getenv("TAIKO_TEST") != "" {
+	if cfg.DisableTaikoAPIs {
 		return
 	}
 	// Add methods under "taiko_" RPC namespace to the available APIs list
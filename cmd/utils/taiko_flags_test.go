@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"bytes"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/eth/catalyst"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+// newTaikoFlagsContext returns a cli context with the Taiko flags parsed from the
+// given arguments.
+func newTaikoFlagsContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range []cli.Flag{&TaikoDisableAPIsFlag, &TxPoolTaikoMinBaseFeeFlag, &TxPoolTaikoAllowZeroFeeCapFlag, &TaikoDevBaseFeeFlag, &TaikoDevBasefeeSharingPctgFlag, &TaikoDevPredeploysFlag} {
+		if err := f.Apply(set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+func TestSetTaikoTxPool(t *testing.T) {
+	var cfg txpool.TaikoConfig
+	setTaikoTxPool(newTaikoFlagsContext(t), &cfg)
+	if !reflect.DeepEqual(cfg, txpool.TaikoConfig{}) {
+		t.Fatalf("expected the protocol defaults, got %+v", cfg)
+	}
+
+	ctx := newTaikoFlagsContext(t, "--txpool.taiko.minbasefee", "Genesis=1, ontake=0", "--txpool.taiko.allowzerofeecap")
+	setTaikoTxPool(ctx, &cfg)
+	want := txpool.TaikoConfig{MinBaseFees: map[string]uint64{"genesis": 1, "ontake": 0}, AllowZeroFeeCap: true}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestSetTaikoDisableAPIs(t *testing.T) {
+	var cfg ethconfig.Config
+	setTaikoPreconf(newTaikoFlagsContext(t), &cfg)
+	if cfg.DisableTaikoAPIs {
+		t.Fatal("expected the Taiko APIs to be enabled by default")
+	}
+	setTaikoPreconf(newTaikoFlagsContext(t, "--taiko.disableapis"), &cfg)
+	if !cfg.DisableTaikoAPIs {
+		t.Fatal("expected the Taiko APIs to be disabled")
+	}
+}
+
+func TestMakeTaikoDevConfig(t *testing.T) {
+	if cfg := MakeTaikoDevConfig(newTaikoFlagsContext(t)); !reflect.DeepEqual(cfg, catalyst.DefaultTaikoDevConfig()) {
+		t.Fatalf("expected the defaults, got %+v", cfg)
+	}
+	cfg := MakeTaikoDevConfig(newTaikoFlagsContext(t, "--taiko.dev.basefee", "1000", "--taiko.dev.basefeesharingpctg", "50"))
+	want := &catalyst.TaikoDevConfig{BaseFee: big.NewInt(1000), BasefeeSharingPctg: 50}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestMakeTaikoDevPredeploys(t *testing.T) {
+	if alloc := MakeTaikoDevPredeploys(newTaikoFlagsContext(t)); alloc != nil {
+		t.Fatalf("expected no predeploys, got %v", alloc)
+	}
+	var (
+		taikoL2 = params.TaikoDevChainConfig().TaikoL2ContractAddress()
+		file    = filepath.Join(t.TempDir(), "predeploys.json")
+	)
+	if err := os.WriteFile(file, []byte(`{"`+taikoL2.Hex()+`": {"code": "0x00", "balance": "0x0"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	alloc := MakeTaikoDevPredeploys(newTaikoFlagsContext(t, "--taiko.dev.predeploys", file))
+	if len(alloc) != 1 || !bytes.Equal(alloc[taikoL2].Code, []byte{0x00}) {
+		t.Fatalf("unexpected predeploys: %v", alloc)
+	}
+}
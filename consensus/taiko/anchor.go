@@ -0,0 +1,242 @@
+package taiko
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// goldenTouchKey is the publicly known private key of the GoldenTouchAccount, which
+// signs the anchor transactions.
+var goldenTouchKey, _ = crypto.HexToECDSA("92954368afd3caa1f3ce3ead0069c1af414054aefe1ef9aeacc1bf426222ce38")
+
+// errAnchorV2NotAccepted is returned if an anchorV2 transaction is built for a block
+// whose Taiko fork doesn't accept TaikoL2.anchorV2 as the anchor call.
+var errAnchorV2NotAccepted = errors.New("anchorV2 is not accepted by the active Taiko fork")
+
+// BaseFeeConfig represents the `LibSharedData.BaseFeeConfig` struct of the protocol,
+// passed to TaikoL2.anchorV2 to calculate the base fee of the L2 block.
+type BaseFeeConfig struct {
+	AdjustmentQuotient     uint8  `json:"adjustmentQuotient"`
+	SharingPctg            uint8  `json:"sharingPctg"`
+	GasIssuancePerSecond   uint32 `json:"gasIssuancePerSecond"`
+	MinGasExcess           uint64 `json:"minGasExcess"`
+	MaxGasIssuancePerBlock uint32 `json:"maxGasIssuancePerBlock"`
+}
+
+// AnchorInfo represents the arguments of a TaikoL2.anchor or TaikoL2.anchorV2 call,
+// which tell the L1 block a L2 block is anchored to.
+type AnchorInfo struct {
+	L1Height      uint64         `json:"l1Height"`
+	L1Hash        *common.Hash   `json:"l1Hash,omitempty"` // Only set by TaikoL2.anchor
+	L1StateRoot   common.Hash    `json:"l1StateRoot"`
+	ParentGasUsed uint32         `json:"parentGasUsed"`
+	BaseFeeConfig *BaseFeeConfig `json:"baseFeeConfig,omitempty"` // Only set by TaikoL2.anchorV2
+}
+
+// EncodeAnchorV2 encodes the calldata of a TaikoL2.anchorV2 call, all arguments
+// are static so each of them takes a single word.
+func EncodeAnchorV2(l1Height uint64, l1StateRoot common.Hash, parentGasUsed uint32, config *BaseFeeConfig) []byte {
+	words := []*big.Int{
+		new(big.Int).SetUint64(l1Height),
+		new(big.Int).SetBytes(l1StateRoot.Bytes()),
+		new(big.Int).SetUint64(uint64(parentGasUsed)),
+		new(big.Int).SetUint64(uint64(config.AdjustmentQuotient)),
+		new(big.Int).SetUint64(uint64(config.SharingPctg)),
+		new(big.Int).SetUint64(uint64(config.GasIssuancePerSecond)),
+		new(big.Int).SetUint64(config.MinGasExcess),
+		new(big.Int).SetUint64(uint64(config.MaxGasIssuancePerBlock)),
+	}
+	data := make([]byte, 0, len(AnchorV2Selector)+len(words)*common.HashLength)
+	data = append(data, AnchorV2Selector...)
+	for _, word := range words {
+		data = append(data, common.LeftPadBytes(word.Bytes(), common.HashLength)...)
+	}
+	return data
+}
+
+// DecodeAnchor decodes the calldata of a TaikoL2.anchor or TaikoL2.anchorV2 call.
+func DecodeAnchor(data []byte) (*AnchorInfo, error) {
+	switch {
+	case bytes.HasPrefix(data, AnchorSelector):
+		// anchor(bytes32 l1BlockHash, bytes32 l1StateRoot, uint64 l1BlockId, uint32 parentGasUsed)
+		words, err := anchorWords(data, 4)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			l1Hash        = common.BytesToHash(words[0])
+			l1Height      uint64
+			parentGasUsed uint64
+		)
+		if l1Height, err = decodeUint(words[2], 64); err != nil {
+			return nil, err
+		}
+		if parentGasUsed, err = decodeUint(words[3], 32); err != nil {
+			return nil, err
+		}
+		return &AnchorInfo{
+			L1Height:      l1Height,
+			L1Hash:        &l1Hash,
+			L1StateRoot:   common.BytesToHash(words[1]),
+			ParentGasUsed: uint32(parentGasUsed),
+		}, nil
+
+	case bytes.HasPrefix(data, AnchorV2Selector):
+		// anchorV2(uint64 anchorBlockId, bytes32 anchorStateRoot, uint32 parentGasUsed, BaseFeeConfig config)
+		words, err := anchorWords(data, 8)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			bits   = []int{64, 0, 32, 8, 8, 32, 64, 32}
+			values = make([]uint64, len(words))
+		)
+		for i, word := range words {
+			if bits[i] == 0 {
+				continue
+			}
+			if values[i], err = decodeUint(word, bits[i]); err != nil {
+				return nil, err
+			}
+		}
+		return &AnchorInfo{
+			L1Height:      values[0],
+			L1StateRoot:   common.BytesToHash(words[1]),
+			ParentGasUsed: uint32(values[2]),
+			BaseFeeConfig: &BaseFeeConfig{
+				AdjustmentQuotient:     uint8(values[3]),
+				SharingPctg:            uint8(values[4]),
+				GasIssuancePerSecond:   uint32(values[5]),
+				MinGasExcess:           values[6],
+				MaxGasIssuancePerBlock: uint32(values[7]),
+			},
+		}, nil
+
+	default:
+		return nil, errors.New("not an anchor call")
+	}
+}
+
+// DecodeAnchorTx decodes the arguments of the given TaikoL2.anchor or TaikoL2.anchorV2
+// transaction, it returns ErrAnchorTxNotFound if the transaction is not an anchor call.
+func (t *Taiko) DecodeAnchorTx(tx *types.Transaction) (*AnchorInfo, error) {
+	if !t.IsAnchorCall(tx) {
+		return nil, ErrAnchorTxNotFound
+	}
+	return DecodeAnchor(tx.Data())
+}
+
+// anchorWords splits the arguments of an anchor call into the given number of words.
+func anchorWords(data []byte, count int) ([][]byte, error) {
+	args := data[len(AnchorSelector):]
+	if len(args) < count*common.HashLength {
+		return nil, fmt.Errorf("anchor calldata too short: have %d bytes, want %d", len(args), count*common.HashLength)
+	}
+	words := make([][]byte, count)
+	for i := range words {
+		words[i] = args[i*common.HashLength : (i+1)*common.HashLength]
+	}
+	return words, nil
+}
+
+// decodeUint decodes an ABI encoded unsigned integer of the given bit size.
+func decodeUint(word []byte, bits int) (uint64, error) {
+	padding := common.HashLength - bits/8
+	if !bytes.Equal(word[:padding], make([]byte, padding)) {
+		return 0, fmt.Errorf("anchor argument overflows uint%d", bits)
+	}
+	return new(big.Int).SetBytes(word[padding:]).Uint64(), nil
+}
+
+// NewAnchorV2Tx builds the TaikoL2.anchorV2 transaction of the given L2 block, signed
+// by the GoldenTouchAccount with the given nonce, so that it passes ValidateAnchorTx.
+func (t *Taiko) NewAnchorV2Tx(
+	header *types.Header,
+	nonce uint64,
+	l1Height uint64,
+	l1StateRoot common.Hash,
+	parentGasUsed uint32,
+	config *BaseFeeConfig,
+) (*types.Transaction, error) {
+	// The selector and the gas limit both come from the active fork.
+	fork := t.chainConfig.TaikoForkAt(header.Number, header.Time)
+	if !hasAnchorSelector(AnchorV2Selector, anchorSelectors[fork]) {
+		return nil, errAnchorV2NotAccepted
+	}
+	if t.chainConfig.GoldenTouchAccount() != crypto.PubkeyToAddress(goldenTouchKey.PublicKey) {
+		return nil, errors.New("unknown key of the golden touch account")
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   t.chainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: common.Big0,
+		GasFeeCap: header.BaseFee,
+		Gas:       fork.Params().AnchorGasLimit,
+		To:        &t.taikoL2Address,
+		Value:     common.Big0,
+		Data:      EncodeAnchorV2(l1Height, l1StateRoot, parentGasUsed, config),
+	})
+	signer := types.MakeSigner(t.chainConfig, header.Number, header.Time)
+
+	sig, err := SignAnchor(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// SignAnchor signs the given hash with the GoldenTouchAccount key, using the fixed
+// nonce k = 1 of the protocol, or k = 2 if the former can't produce a signature.
+// The signature is in the [R || S || V] format, with V being 0 or 1.
+func SignAnchor(hash []byte) ([]byte, error) {
+	for k := int64(1); k <= 2; k++ {
+		if sig := signWithK(hash, big.NewInt(k)); sig != nil {
+			return sig, nil
+		}
+	}
+	return nil, errors.New("failed to sign anchor transaction")
+}
+
+// signWithK computes the ECDSA signature of the given hash with the fixed nonce k,
+// it returns nil if the signature is not valid.
+func signWithK(hash []byte, k *big.Int) []byte {
+	var (
+		curve = crypto.S256()
+		n     = curve.Params().N
+	)
+	x, y := curve.ScalarBaseMult(k.Bytes())
+
+	// r = (k * G).x mod n, s = k^-1 * (hash + r * key) mod n
+	r := new(big.Int).Mod(x, n)
+	s := new(big.Int).Mul(r, goldenTouchKey.D)
+	s.Add(s, new(big.Int).SetBytes(hash))
+	s.Mul(s, new(big.Int).ModInverse(k, n))
+	s.Mod(s, n)
+	if r.Sign() == 0 || s.Sign() == 0 {
+		return nil
+	}
+	// The recovery ID can only be 0 or 1 in transactions, so (k * G).x >= n is not
+	// allowed, which never happens with a small k anyway.
+	if x.Cmp(n) >= 0 {
+		return nil
+	}
+	v := byte(y.Bit(0))
+
+	// Only the lower S values are allowed by EIP-2.
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+		v ^= 1
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = v
+
+	return sig
+}
@@ -0,0 +1,183 @@
+package taiko_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the fixed k signatures against the vectors of the protocol's client, the
+// R values are the x coordinates of G and 2G.
+func TestSignAnchor(t *testing.T) {
+	sig, err := taiko.SignAnchor(common.Hex2Bytes("44943399d1507f3ce7525e9be2f987c3db9136dc759cb7f92f742154196868b9"))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"+
+			"782a1e70872ecc1a9f740dd445664543f8b7598c94582720bca9a8c48d6a4766"+
+			"01",
+		common.Bytes2Hex(sig),
+	)
+}
+
+func TestEncodeAnchorV2(t *testing.T) {
+	data := taiko.EncodeAnchorV2(
+		20_000_000,
+		common.HexToHash("0xc0ffee"),
+		1_000_000,
+		&taiko.BaseFeeConfig{
+			AdjustmentQuotient:     8,
+			SharingPctg:            75,
+			GasIssuancePerSecond:   5_000_000,
+			MinGasExcess:           1_340_000_000,
+			MaxGasIssuancePerBlock: 600_000_000,
+		},
+	)
+	assert.Equal(t, "fd85eb2d"+
+		"0000000000000000000000000000000000000000000000000000000001312d00"+
+		"0000000000000000000000000000000000000000000000000000000000c0ffee"+
+		"00000000000000000000000000000000000000000000000000000000000f4240"+
+		"0000000000000000000000000000000000000000000000000000000000000008"+
+		"000000000000000000000000000000000000000000000000000000000000004b"+
+		"00000000000000000000000000000000000000000000000000000000004c4b40"+
+		"000000000000000000000000000000000000000000000000000000004fdec700"+
+		"0000000000000000000000000000000000000000000000000000000023c34600",
+		common.Bytes2Hex(data),
+	)
+}
+
+func TestDecodeAnchor(t *testing.T) {
+	var (
+		hash = common.HexToHash("0xbeef")
+		root = common.HexToHash("0xc0ffee")
+		cfg  = &taiko.BaseFeeConfig{
+			AdjustmentQuotient:     8,
+			SharingPctg:            75,
+			GasIssuancePerSecond:   5_000_000,
+			MinGasExcess:           1_340_000_000,
+			MaxGasIssuancePerBlock: 600_000_000,
+		}
+	)
+	info, err := taiko.DecodeAnchor(taiko.EncodeAnchorV2(20_000_000, root, 1_000_000, cfg))
+	require.NoError(t, err)
+	assert.Equal(t, &taiko.AnchorInfo{L1Height: 20_000_000, L1StateRoot: root, ParentGasUsed: 1_000_000, BaseFeeConfig: cfg}, info)
+
+	legacy := append(append([]byte{}, taiko.AnchorSelector...), hash.Bytes()...)
+	legacy = append(legacy, root.Bytes()...)
+	legacy = append(legacy, common.LeftPadBytes(big.NewInt(20_000_000).Bytes(), 32)...)
+	legacy = append(legacy, common.LeftPadBytes(big.NewInt(1_000_000).Bytes(), 32)...)
+
+	info, err = taiko.DecodeAnchor(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, &taiko.AnchorInfo{L1Height: 20_000_000, L1Hash: &hash, L1StateRoot: root, ParentGasUsed: 1_000_000}, info)
+
+	// Truncated calldata, overflowing arguments and other calls are rejected.
+	_, err = taiko.DecodeAnchor(legacy[:len(legacy)-1])
+	assert.Error(t, err)
+
+	overflow := append([]byte{}, legacy...)
+	overflow[len(overflow)-5] = 1
+	_, err = taiko.DecodeAnchor(overflow)
+	assert.Error(t, err)
+
+	_, err = taiko.DecodeAnchor(common.Hex2Bytes("a9059cbb"))
+	assert.Error(t, err)
+}
+
+func TestNewAnchorV2Tx(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Taiko = true
+	config.OntakeBlock = big.NewInt(10)
+	engine := taiko.New(&config)
+
+	var (
+		header = &types.Header{Number: big.NewInt(10), Time: 9000, BaseFee: big.NewInt(params.InitialBaseFee)}
+		root   = common.HexToHash("0xc0ffee")
+		cfg    = &taiko.BaseFeeConfig{AdjustmentQuotient: 8, SharingPctg: 75}
+	)
+	tx, err := engine.NewAnchorV2Tx(header, 7, 100, root, 21000, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), tx.Nonce())
+	assert.Equal(t, taiko.EncodeAnchorV2(100, root, 21000, cfg), tx.Data())
+	assert.Equal(t, params.TaikoOntake.Params().AnchorGasLimit, tx.Gas())
+
+	valid, err := engine.ValidateAnchorTx(tx, header)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.NoError(t, engine.VerifyAnchorTransactions(header, types.Transactions{tx}))
+
+	info, err := engine.DecodeAnchorTx(tx)
+	require.NoError(t, err)
+	assert.Equal(t, &taiko.AnchorInfo{L1Height: 100, L1StateRoot: root, ParentGasUsed: 21000, BaseFeeConfig: cfg}, info)
+
+	// The signature is deterministic.
+	again, err := engine.NewAnchorV2Tx(header, 7, 100, root, 21000, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, tx.Hash(), again.Hash())
+
+	// Blocks before Ontake use the legacy anchor.
+	_, err = engine.NewAnchorV2Tx(&types.Header{Number: big.NewInt(9), BaseFee: header.BaseFee}, 0, 100, root, 21000, cfg)
+	assert.ErrorContains(t, err, "anchorV2 is not accepted")
+
+	// Anchor transactions can't be signed for a custom golden touch account.
+	custom := config
+	custom.GoldenTouchAddress = &common.Address{0x01}
+	_, err = taiko.New(&custom).NewAnchorV2Tx(header, 7, 100, root, 21000, cfg)
+	assert.Error(t, err)
+}
+
+// mainnetAnchorV2Tx is an anchorV2 transaction of Taiko mainnet, in the format of
+// testdata/mainnet_anchor_v2_txs.json. Raw is the signed transaction as returned by
+// eth_getRawTransactionByHash.
+type mainnetAnchorV2Tx struct {
+	Block uint64        `json:"block"`
+	Hash  common.Hash   `json:"hash"`
+	Raw   hexutil.Bytes `json:"raw"`
+}
+
+// Tests that the anchorV2 transactions of Taiko mainnet are reproduced byte for byte:
+// their calldata by EncodeAnchorV2, their signature by SignAnchor and the whole
+// transaction by NewAnchorV2Tx.
+func TestMainnetAnchorV2Txs(t *testing.T) {
+	blob, err := os.ReadFile("testdata/mainnet_anchor_v2_txs.json")
+	require.NoError(t, err)
+	var txs []mainnetAnchorV2Tx
+	require.NoError(t, json.Unmarshal(blob, &txs))
+	if len(txs) == 0 {
+		t.Skip("no mainnet anchorV2 transactions in testdata, see testdata/readme.md")
+	}
+	config, _ := params.TaikoChainConfigByNetworkID(params.TaikoMainnetNetworkID.Uint64())
+	engine := taiko.New(config)
+
+	for _, want := range txs {
+		var tx types.Transaction
+		require.NoError(t, tx.UnmarshalBinary(want.Raw), "block %d", want.Block)
+		require.Equal(t, want.Hash, tx.Hash(), "block %d", want.Block)
+
+		info, err := engine.DecodeAnchorTx(&tx)
+		require.NoError(t, err, "block %d", want.Block)
+		assert.Equal(t, tx.Data(), taiko.EncodeAnchorV2(info.L1Height, info.L1StateRoot, info.ParentGasUsed, info.BaseFeeConfig), "block %d", want.Block)
+
+		header := &types.Header{Number: new(big.Int).SetUint64(want.Block), BaseFee: tx.GasFeeCap()}
+		sig, err := taiko.SignAnchor(types.MakeSigner(config, header.Number, header.Time).Hash(&tx).Bytes())
+		require.NoError(t, err, "block %d", want.Block)
+		v, r, s := tx.RawSignatureValues()
+		assert.Equal(t, common.BigToHash(r).Bytes(), sig[:32], "block %d", want.Block)
+		assert.Equal(t, common.BigToHash(s).Bytes(), sig[32:64], "block %d", want.Block)
+		assert.Equal(t, v.Uint64(), uint64(sig[64]), "block %d", want.Block)
+
+		rebuilt, err := engine.NewAnchorV2Tx(header, tx.Nonce(), info.L1Height, info.L1StateRoot, info.ParentGasUsed, info.BaseFeeConfig)
+		require.NoError(t, err, "block %d", want.Block)
+		raw, err := rebuilt.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, []byte(want.Raw), raw, "block %d", want.Block)
+	}
+}
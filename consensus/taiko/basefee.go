@@ -0,0 +1,340 @@
+package taiko
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrInvalidBaseFee  = errors.New("invalid base fee")
+	errNoBaseFeeConfig = errors.New("anchor transaction has no base fee config")
+
+	// wad is the scaling factor of the fixed point numbers, `LibFixedPointMath.SCALING_FACTOR`.
+	wad = big.NewInt(1e18)
+
+	// maxExpInput is the largest input of expWad, `LibFixedPointMath.MAX_EXP_INPUT`.
+	maxExpInput, _ = new(big.Int).SetString("135305999368893231588", 10)
+
+	// minExpInput is the largest input of expWad rounded down to zero.
+	minExpInput, _ = new(big.Int).SetString("-42139678854452767551", 10)
+
+	// l2GasStateGas is the gas allowance for reading the gas state of TaikoL2.
+	l2GasStateGas = uint64(100_000)
+)
+
+// L2GasState is the state of the TaikoL2 contract at the end of the parent block,
+// from which the base fee of an Ontake block is calculated.
+type L2GasState struct {
+	GasExcess uint64 // TaikoL2.parentGasExcess
+	GasTarget uint64 // TaikoL2.parentGasTarget, zero before the first Ontake block
+	Timestamp uint64 // TaikoL2.parentTimestamp
+}
+
+// CalcBaseFeeOntake calculates the base fee of an Ontake block from the gas state of
+// its parent, the same as `TaikoL2.getBasefeeV2` does. It also returns the gas target
+// and gas excess the anchor transaction stores for the next block.
+func CalcBaseFeeOntake(
+	parent *L2GasState,
+	timestamp uint64,
+	parentGasUsed uint32,
+	config *BaseFeeConfig,
+) (baseFee *big.Int, gasTarget uint64, gasExcess uint64, err error) {
+	if timestamp < parent.Timestamp {
+		return nil, 0, 0, fmt.Errorf("timestamp %d older than TaikoL2 parent timestamp %d", timestamp, parent.Timestamp)
+	}
+	gasTarget = uint64(config.GasIssuancePerSecond) * uint64(config.AdjustmentQuotient)
+	gasTarget, gasExcess = adjustExcess(parent.GasTarget, gasTarget, parent.GasExcess)
+
+	issuance := new(big.Int).SetUint64(timestamp - parent.Timestamp)
+	issuance.Mul(issuance, new(big.Int).SetUint64(uint64(config.GasIssuancePerSecond)))
+	if config.MaxGasIssuancePerBlock != 0 && issuance.Cmp(new(big.Int).SetUint64(uint64(config.MaxGasIssuancePerBlock))) > 0 {
+		issuance.SetUint64(uint64(config.MaxGasIssuancePerBlock))
+	}
+	if !issuance.IsUint64() {
+		return nil, 0, 0, fmt.Errorf("gas issuance overflows uint64: %v", issuance)
+	}
+	baseFee, gasExcess = calc1559BaseFee(gasTarget, gasExcess, issuance.Uint64(), parentGasUsed, config.MinGasExcess)
+
+	return baseFee, gasTarget, gasExcess, nil
+}
+
+// VerifyBaseFee checks that the base fee of the given Ontake block is the one that
+// TaikoL2.anchorV2 calculates from the gas state of the parent block, read with the
+// given EVM, and the base fee config of the anchor transaction.
+func (t *Taiko) VerifyBaseFee(header *types.Header, txs types.Transactions, evm *vm.EVM) error {
+	if len(txs) == 0 {
+		return ErrAnchorTxNotFound
+	}
+	anchor, err := t.DecodeAnchorTx(txs[0])
+	if err != nil {
+		return err
+	}
+	if anchor.BaseFeeConfig == nil {
+		return errNoBaseFeeConfig
+	}
+	parent, err := t.ReadL2GasState(evm)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		// TaikoL2 is not deployed, e.g. on a test chain, so the base fee is not
+		// checked by the anchor transaction either.
+		return nil
+	}
+	baseFee, _, _, err := CalcBaseFeeOntake(parent, header.Time, anchor.ParentGasUsed, anchor.BaseFeeConfig)
+	if err != nil {
+		return err
+	}
+	if header.BaseFee == nil || header.BaseFee.Cmp(baseFee) != 0 {
+		return fmt.Errorf("%w: have %v, want %v", ErrInvalidBaseFee, header.BaseFee, baseFee)
+	}
+	return nil
+}
+
+// ReadL2GasState reads the gas state of TaikoL2 with the given EVM, it returns nil
+// if the contract is not deployed.
+func (t *Taiko) ReadL2GasState(evm *vm.EVM) (*L2GasState, error) {
+	if len(evm.StateDB.GetCode(t.taikoL2Address)) == 0 {
+		return nil, nil
+	}
+	var (
+		getters = []string{"parentGasExcess()", "parentGasTarget()", "parentTimestamp()"}
+		values  = make([]uint64, len(getters))
+	)
+	for i, getter := range getters {
+		ret, _, err := evm.StaticCall(vm.AccountRef(common.Address{}), t.taikoL2Address, crypto.Keccak256([]byte(getter))[:4], l2GasStateGas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call TaikoL2.%s: %w", getter, err)
+		}
+		if len(ret) != common.HashLength {
+			return nil, fmt.Errorf("unexpected TaikoL2.%s result: %x", getter, ret)
+		}
+		if values[i], err = decodeUint(ret, 64); err != nil {
+			return nil, err
+		}
+	}
+	return &L2GasState{GasExcess: values[0], GasTarget: values[1], Timestamp: values[2]}, nil
+}
+
+// MinGasExcessForBaseFee returns the smallest gas excess at which the bonding curve
+// of the given gas target reaches the given base fee, which is the MinGasExcess of a
+// BaseFeeConfig keeping the base fee at or above it. The curve skips some base fees
+// if they exceed the gas target, an error is returned for those.
+func MinGasExcessForBaseFee(gasTarget uint64, baseFee *big.Int) (uint64, error) {
+	if gasTarget == 0 || baseFee == nil || baseFee.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid gas target %d or base fee %v", gasTarget, baseFee)
+	}
+	// The curve is flat beyond maxExpInput, search up to the first gas excess there.
+	maxExcess := new(big.Int).Mul(maxExpInput, new(big.Int).SetUint64(gasTarget))
+	maxExcess.Quo(maxExcess, wad)
+	if !maxExcess.IsUint64() {
+		maxExcess.SetUint64(math.MaxUint64)
+	}
+	lo, hi := uint64(0), maxExcess.Uint64()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if basefee(gasTarget, mid).Cmp(baseFee) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if fee := basefee(gasTarget, lo); fee.Cmp(baseFee) != 0 {
+		return 0, fmt.Errorf("base fee %v not reachable with gas target %d, nearest %v", baseFee, gasTarget, fee)
+	}
+	return lo, nil
+}
+
+// adjustExcess adjusts the gas excess to keep the base fee when the gas target changes,
+// following `LibEIP1559.adjustExcess`:
+//
+//	newGasExcess = newGasTarget * ln(newGasTarget / oldGasTarget) + oldGasExcess * newGasTarget / oldGasTarget
+func adjustExcess(oldGasTarget, newGasTarget, oldGasExcess uint64) (uint64, uint64) {
+	if oldGasTarget == 0 {
+		return newGasTarget, oldGasExcess
+	}
+	if newGasTarget == 0 || oldGasTarget == newGasTarget {
+		return oldGasTarget, oldGasExcess
+	}
+	ratio := new(big.Int).SetUint64(newGasTarget)
+	ratio.Mul(ratio, wad)
+	ratio.Quo(ratio, new(big.Int).SetUint64(oldGasTarget))
+
+	excess := lnWad(ratio)
+	excess.Mul(excess, new(big.Int).SetUint64(newGasTarget))
+	excess.Add(excess, ratio.Mul(ratio, new(big.Int).SetUint64(oldGasExcess)))
+	excess.Quo(excess, wad)
+
+	switch {
+	case excess.Sign() < 0:
+		return newGasTarget, 0
+	case !excess.IsUint64():
+		return newGasTarget, math.MaxUint64
+	default:
+		return newGasTarget, excess.Uint64()
+	}
+}
+
+// calc1559BaseFee adds the gas used by the parent block to the gas excess, subtracts
+// the gas issued since the parent block and returns the base fee of the resulting gas
+// excess, following `LibEIP1559.calc1559BaseFee`.
+func calc1559BaseFee(gasTarget, gasExcess, gasIssuance uint64, parentGasUsed uint32, minGasExcess uint64) (*big.Int, uint64) {
+	excess := new(big.Int).SetUint64(gasExcess)
+	excess.Add(excess, new(big.Int).SetUint64(uint64(parentGasUsed)))
+	if issuance := new(big.Int).SetUint64(gasIssuance); excess.Cmp(issuance) > 0 {
+		excess.Sub(excess, issuance)
+	} else {
+		excess.SetUint64(1)
+	}
+	if excess.Cmp(new(big.Int).SetUint64(minGasExcess)) < 0 {
+		excess.SetUint64(minGasExcess)
+	}
+	if !excess.IsUint64() {
+		excess.SetUint64(math.MaxUint64)
+	}
+	return basefee(gasTarget, excess.Uint64()), excess.Uint64()
+}
+
+// basefee returns the spot price of the bonding curve at the given gas excess,
+// following `LibEIP1559.basefee`, which never goes below 1 wei:
+//
+//	basefee = max(exp(gasExcess / gasTarget) / gasTarget, 1)
+func basefee(gasTarget, gasExcess uint64) *big.Int {
+	if gasTarget == 0 {
+		return big.NewInt(1)
+	}
+	target := new(big.Int).SetUint64(gasTarget)
+
+	input := new(big.Int).SetUint64(gasExcess)
+	input.Mul(input, wad)
+	input.Quo(input, target)
+	if input.Cmp(maxExpInput) > 0 {
+		input.Set(maxExpInput)
+	}
+	fee := expWad(input)
+	fee.Quo(fee, wad)
+	if fee.Quo(fee, target).Sign() == 0 {
+		fee.SetInt64(1)
+	}
+	return fee
+}
+
+// expWad returns e^x of the given fixed point number with 18 decimals, with the
+// rational approximation of `LibFixedPointMath.exp`. The input must not exceed
+// maxExpInput.
+func expWad(x *big.Int) *big.Int {
+	if x.Cmp(minExpInput) <= 0 {
+		return new(big.Int)
+	}
+	// Convert to (-42, 136) * 2^96 and reduce the range to (-1/2 ln 2, 1/2 ln 2) * 2^96
+	// by factoring out powers of two, so that exp(x) = exp(x') * 2^k.
+	x = new(big.Int).Lsh(x, 78)
+	x.Quo(x, bigPow(5, 18))
+
+	k := new(big.Int).Lsh(x, 96)
+	k.Quo(k, bigInt("54916777467707473351141471128"))
+	k.Add(k, new(big.Int).Lsh(common.Big1, 95))
+	k.Rsh(k, 96)
+	x.Sub(x, new(big.Int).Mul(k, bigInt("54916777467707473351141471128")))
+
+	// Evaluate the (6, 7)-term rational approximation, p is made monic.
+	y := new(big.Int).Add(x, bigInt("1346386616545796478920950773328"))
+	y = mulShr96(y, x)
+	y.Add(y, bigInt("57155421227552351082224309758442"))
+	p := new(big.Int).Add(y, x)
+	p.Sub(p, bigInt("94201549194550492254356042504812"))
+	p = mulShr96(p, y)
+	p.Add(p, bigInt("28719021644029726153956944680412240"))
+	p.Mul(p, x)
+	p.Add(p, new(big.Int).Lsh(bigInt("4385272521454847904659076985693276"), 96))
+
+	q := new(big.Int).Sub(x, bigInt("2855989394907223263936484059900"))
+	for _, c := range []string{
+		"50020603652535783019961831881945",
+		"-533845033583426703283633433725380",
+		"3604857256930695427073651918091429",
+		"-14423608567350463180887372962807573",
+		"26449188498355588339934803723976023",
+	} {
+		q = mulShr96(q, x)
+		q.Add(q, bigInt(c))
+	}
+	// r is in the range (0.09, 0.25) * 2^96, scale it back to 18 decimals and
+	// multiply by 2^k.
+	r := new(big.Int).Quo(p, q)
+	r.Mul(r, bigInt("3822833074963236453042738258902158003155416615667"))
+	return r.Rsh(r, uint(195-k.Int64()))
+}
+
+// lnWad returns ln(x) of the given positive fixed point number with 18 decimals,
+// with the rational approximation of `FixedPointMathLib.lnWad`.
+func lnWad(x *big.Int) *big.Int {
+	// Reduce the range of x to (1, 2) * 2^96, so that ln(x) = ln(x') + k * ln(2).
+	k := int64(x.BitLen() - 1 - 96)
+	if k >= 0 {
+		x = new(big.Int).Rsh(x, uint(k))
+	} else {
+		x = new(big.Int).Lsh(x, uint(-k))
+	}
+	// Evaluate the (8, 8)-term rational approximation, p is made monic.
+	p := new(big.Int).Add(x, bigInt("3273285459638523848632254066296"))
+	for _, c := range []string{
+		"24828157081833163892658089445524",
+		"43456485725739037958740375743393",
+		"-11111509109440967052023855526967",
+		"-45023709667254063763336534515857",
+		"-14706773417378608786704636184526",
+	} {
+		p = mulShr96(p, x)
+		p.Add(p, bigInt(c))
+	}
+	p.Mul(p, x)
+	p.Sub(p, new(big.Int).Lsh(bigInt("795164235651350426258249787498"), 96))
+
+	q := new(big.Int).Add(x, bigInt("5573035233440673466300451813936"))
+	for _, c := range []string{
+		"71694874799317883764090561454958",
+		"283447036172924575727196451306956",
+		"401686690394027663651624208769553",
+		"204048457590392012362485061816622",
+		"31853899698501571402653359427138",
+		"909429971244387300277376558375",
+	} {
+		q = mulShr96(q, x)
+		q.Add(q, bigInt(c))
+	}
+	// r is in the range (0, 0.125) * 2^96, scale it to 18 decimals, add k * ln(2)
+	// and the constant term of the approximation.
+	r := new(big.Int).Quo(p, q)
+	r.Mul(r, bigInt("1677202110996718588342820967067443963516166"))
+	r.Add(r, new(big.Int).Mul(bigInt("16597577552685614221487285958193947469193820559219878177908093499208371"), big.NewInt(k)))
+	r.Add(r, bigInt("600920179829731861736702779321621459595472258049074101567377883020018308"))
+	return r.Rsh(r, 174)
+}
+
+// mulShr96 returns (a * b) >> 96, rounding towards negative infinity.
+func mulShr96(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Rsh(r, 96)
+}
+
+// bigPow returns a ** b as a big integer.
+func bigPow(a, b int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(a), big.NewInt(b), nil)
+}
+
+// bigInt parses the given decimal constant.
+func bigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid constant " + s)
+	}
+	return n
+}
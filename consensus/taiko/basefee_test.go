@@ -0,0 +1,230 @@
+package taiko
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the fixed point math against the vectors of solady's FixedPointMathLib tests
+// (test/FixedPointMathLib.t.sol: testExpWad, testLnWad, testLnWadSmall and
+// testLnWadBig), which LibFixedPointMath.exp is derived from.
+func TestFixedPointMath(t *testing.T) {
+	for x, want := range map[string]string{
+		"-42139678854452767551": "0",
+		"-41446531673892822312": "1",
+		"-3000000000000000000":  "49787068367863942",
+		"-1000000000000000000":  "367879441171442321",
+		"0":                     "1000000000000000000",
+		"1000000000000000000":   "2718281828459045235",
+		"5000000000000000000":   "148413159102576603421",
+		"10000000000000000000":  "22026465794806716516980",
+		"135305999368893231588": "57896044618658097650144101621524338577433870140581303254786265309376407432913",
+	} {
+		assert.Equal(t, want, expWad(bigInt(x)).String(), "exp(%s)", x)
+	}
+	for x, want := range map[string]string{
+		"1":                    "-41446531673892822313",
+		"42":                   "-37708862055609454007",
+		"10000":                "-32236191301916639577",
+		"1000000000":           "-20723265836946411157",
+		"1000000000000000000":  "0",
+		"2718281828459045235":  "999999999999999999",
+		"11723640096265400935": "2461607324344817918",
+	} {
+		assert.Equal(t, want, lnWad(bigInt(x)).String(), "ln(%s)", x)
+	}
+	for bits, want := range map[uint]string{
+		128: "47276307437780177293",
+		170: "76388489021297880288",
+		255: "135305999368893231589",
+	} {
+		assert.Equal(t, want, lnWad(new(big.Int).Lsh(common.Big1, bits)).String(), "ln(2^%d)", bits)
+	}
+}
+
+// bigFloat64 returns the given integer as a float64.
+func bigFloat64(x *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(x).Float64()
+	return f
+}
+
+func TestBasefee(t *testing.T) {
+	// The Ontake config of the protocol (TaikoL1.getConfig) sets the minimum gas
+	// excess to 1_340_000_000, which "correspond to 0.008847185 gwei basefee", the
+	// minimum base fee of the fork.
+	assert.Equal(t, params.TaikoOntake.Params().MinBaseFee, basefee(5_000_000*8, 1_340_000_000))
+
+	// Elsewhere the curve is exp(gasExcess / gasTarget) / gasTarget, rounded down to
+	// the wei.
+	for _, target := range []uint64{8_000_000, 40_000_000, 60_000_000} {
+		for excess := 30 * target; excess <= 60*target; excess += target * 7 / 2 {
+			want := math.Exp(float64(excess)/float64(target)) / float64(target)
+			assert.InDelta(t, want, bigFloat64(basefee(target, excess)), 1+want*1e-9, "basefee(%d, %d)", target, excess)
+		}
+	}
+	// The exponent is capped at LibFixedPointMath.MAX_EXP_INPUT.
+	capped := new(big.Int).Quo(expWad(maxExpInput), wad)
+	assert.Equal(t, capped.Quo(capped, big.NewInt(40_000_000)), basefee(40_000_000, math.MaxUint64))
+	assert.Equal(t, big.NewInt(1), basefee(0, 1_340_000_000))
+
+	// The base fee is at least 1 wei, as the `.max(1)` of LibEIP1559.basefee.
+	assert.Equal(t, big.NewInt(1), basefee(40_000_000, 0))
+	assert.Equal(t, big.NewInt(1), basefee(2, 0))
+	assert.Equal(t, big.NewInt(1), basefee(math.MaxUint64, 1))
+}
+
+func TestMinGasExcessForBaseFee(t *testing.T) {
+	// The whole curve is at or above 1 wei.
+	excess, err := MinGasExcessForBaseFee(40_000_000, common.Big1)
+	require.NoError(t, err)
+	assert.Zero(t, excess)
+
+	for _, baseFee := range []int64{2, 8_847_185, params.GWei / 100, 39_999_999} {
+		excess, err := MinGasExcessForBaseFee(40_000_000, big.NewInt(baseFee))
+		require.NoError(t, err, "base fee %d", baseFee)
+		assert.Equal(t, big.NewInt(baseFee), basefee(40_000_000, excess), "base fee %d", baseFee)
+		assert.Equal(t, -1, basefee(40_000_000, excess-1).Cmp(big.NewInt(baseFee)), "base fee %d", baseFee)
+	}
+	// The minimum gas excess of the mainnet config is above the smallest one.
+	excess, err = MinGasExcessForBaseFee(40_000_000, params.TaikoOntake.Params().MinBaseFee)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, excess, uint64(1_340_000_000))
+
+	// Base fees above the gas target grow by more than one wei per gas, so the ones
+	// in between are skipped by the curve.
+	excess, err = MinGasExcessForBaseFee(40_000_000, big.NewInt(params.GWei))
+	require.NoError(t, err)
+	require.Greater(t, new(big.Int).Sub(basefee(40_000_000, excess+1), big.NewInt(params.GWei)).Int64(), int64(1))
+	_, err = MinGasExcessForBaseFee(40_000_000, big.NewInt(params.GWei+1))
+	assert.Error(t, err)
+	_, err = MinGasExcessForBaseFee(0, big.NewInt(1))
+	assert.Error(t, err)
+}
+
+func TestAdjustExcess(t *testing.T) {
+	// The gas excess is rescaled to keep the base fee when the gas target changes:
+	// newGasTarget * ln(newGasTarget / oldGasTarget) + oldGasExcess * newGasTarget / oldGasTarget.
+	for _, tt := range []struct{ oldTarget, newTarget, oldExcess uint64 }{
+		{40_000_000, 20_000_000, 1_500_000_000},
+		{40_000_000, 60_000_000, 1_500_000_000},
+		{8_000_000, 40_000_000, 300_000_000},
+	} {
+		target, excess := adjustExcess(tt.oldTarget, tt.newTarget, tt.oldExcess)
+		assert.Equal(t, tt.newTarget, target)
+
+		ratio := float64(tt.newTarget) / float64(tt.oldTarget)
+		assert.InEpsilon(t, float64(tt.newTarget)*math.Log(ratio)+float64(tt.oldExcess)*ratio, float64(excess), 1e-9, "%+v", tt)
+		assert.InEpsilon(t, bigFloat64(basefee(tt.oldTarget, tt.oldExcess)), bigFloat64(basefee(target, excess)), 1e-6, "%+v", tt)
+	}
+	// The excess can't go below zero.
+	target, excess := adjustExcess(40_000_000, 20_000_000, 0)
+	assert.Equal(t, uint64(20_000_000), target)
+	assert.Equal(t, uint64(0), excess)
+
+	target, excess = adjustExcess(0, 40_000_000, 1_500_000_000)
+	assert.Equal(t, uint64(40_000_000), target)
+	assert.Equal(t, uint64(1_500_000_000), excess)
+
+	target, excess = adjustExcess(40_000_000, 0, 1_500_000_000)
+	assert.Equal(t, uint64(40_000_000), target)
+	assert.Equal(t, uint64(1_500_000_000), excess)
+}
+
+func TestCalcBaseFeeOntake(t *testing.T) {
+	// The Ontake config of the protocol, TaikoL1.getConfig.
+	config := &BaseFeeConfig{
+		AdjustmentQuotient:     8,
+		SharingPctg:            75,
+		GasIssuancePerSecond:   5_000_000,
+		MinGasExcess:           1_340_000_000,
+		MaxGasIssuancePerBlock: 600_000_000,
+	}
+	tests := []struct {
+		name          string
+		parent        *L2GasState
+		timestamp     uint64
+		parentGasUsed uint32
+		wantExcess    uint64
+	}{
+		{
+			name:          "minGasExcess",
+			parent:        &L2GasState{GasExcess: 1_340_000_000, GasTarget: 40_000_000, Timestamp: 100},
+			timestamp:     112,
+			parentGasUsed: 1_000_000,
+			wantExcess:    1_340_000_000,
+		},
+		{
+			name:          "congested",
+			parent:        &L2GasState{GasExcess: 2_000_000_000, GasTarget: 40_000_000, Timestamp: 100},
+			timestamp:     102,
+			parentGasUsed: 30_000_000,
+			wantExcess:    2_020_000_000,
+		},
+		{
+			name:          "maxGasIssuancePerBlock",
+			parent:        &L2GasState{GasExcess: 2_600_000_000, GasTarget: 40_000_000, Timestamp: 100},
+			timestamp:     1100,
+			parentGasUsed: 20_000_000,
+			wantExcess:    2_020_000_000,
+		},
+		{
+			name:          "firstOntakeBlock",
+			parent:        &L2GasState{GasExcess: 2_000_000_000, Timestamp: 100},
+			timestamp:     102,
+			parentGasUsed: 30_000_000,
+			wantExcess:    2_020_000_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseFee, target, excess, err := CalcBaseFeeOntake(tt.parent, tt.timestamp, tt.parentGasUsed, config)
+			require.NoError(t, err)
+			// The gas excess is updated as in LibEIP1559.calc1559BaseFee, and the
+			// base fee is the one of the curve at the new gas excess.
+			assert.Equal(t, basefee(40_000_000, tt.wantExcess), baseFee)
+			assert.Equal(t, uint64(40_000_000), target)
+			assert.Equal(t, tt.wantExcess, excess)
+		})
+	}
+
+	_, _, _, err := CalcBaseFeeOntake(&L2GasState{Timestamp: 100}, 99, 0, config)
+	assert.Error(t, err)
+}
+
+func TestVerifyBaseFee(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Taiko = true
+	config.OntakeBlock = common.Big0
+	engine := New(&config)
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	evm := vm.NewEVM(vm.BlockContext{BlockNumber: common.Big1}, vm.TxContext{}, statedb, &config, vm.Config{})
+
+	var (
+		header = &types.Header{Number: common.Big1, Time: 40_000_002, BaseFee: big.NewInt(8_847_185)}
+		cfg    = &BaseFeeConfig{AdjustmentQuotient: 8, GasIssuancePerSecond: 5_000_000, MinGasExcess: 1_340_000_000}
+	)
+	tx, err := engine.NewAnchorV2Tx(header, 0, 1, common.Hash{}, 1_000_000, cfg)
+	require.NoError(t, err)
+
+	// Not checked if TaikoL2 is not deployed.
+	assert.NoError(t, engine.VerifyBaseFee(&types.Header{Number: common.Big1, BaseFee: common.Big1}, types.Transactions{tx}, evm))
+
+	// A TaikoL2 stub returning 40_000_000 for all the getters.
+	statedb.SetCode(engine.taikoL2Address, common.FromHex("0x6302625a0060005260206000f3"))
+	assert.NoError(t, engine.VerifyBaseFee(header, types.Transactions{tx}, evm))
+
+	invalid := types.CopyHeader(header)
+	invalid.BaseFee = big.NewInt(8_847_186)
+	assert.ErrorIs(t, engine.VerifyBaseFee(invalid, types.Transactions{tx}, evm), ErrInvalidBaseFee)
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -27,16 +26,41 @@ var (
 	ErrEmptyBasefee         = errors.New("empty base fee")
 	ErrEmptyWithdrawalsHash = errors.New("withdrawals hash missing")
 	ErrAnchorTxNotFound     = errors.New("anchor transaction not found")
+	ErrUnexpectedAnchorCall = errors.New("anchor call found after the first transaction")
 
-	GoldenTouchAccount   = common.HexToAddress("0x0000777735367b36bC9B61C50022d9D0700dB4Ec")
-	TaikoL2AddressSuffix = "10001"
-	AnchorSelector       = crypto.Keccak256([]byte("anchor(bytes32,bytes32,uint64,uint32)"))[:4]
-	AnchorV2Selector     = crypto.Keccak256(
-		[]byte("anchorV2(uint64,bytes32,uint32,(uint8,uint8,uint32,uint64,uint32))"),
-	)[:4]
-	AnchorGasLimit = uint64(250_000)
+	// GoldenTouchAccount is the default sender of the anchor transactions, the chain
+	// config may override it, see params.ChainConfig.GoldenTouchAccount.
+	GoldenTouchAccount = params.DefaultGoldenTouchAccount
+
+	// Selectors of the anchor methods, and the anchor gas limit before Ontake. The
+	// anchor parameters of each fork are defined by params.TaikoForkParams.
+	AnchorSelector   = anchorSelectors[params.TaikoGenesis][0]
+	AnchorV2Selector = anchorSelectors[params.TaikoOntake][0]
+	AnchorGasLimit   = params.TaikoGenesis.Params().AnchorGasLimit
 )
 
+// anchorSelectors are the selectors of the anchor methods accepted by each fork.
+var anchorSelectors = func() map[params.TaikoFork][][]byte {
+	selectors := make(map[params.TaikoFork][][]byte)
+	for fork := params.TaikoGenesis; fork <= params.LatestTaikoFork; fork++ {
+		for _, method := range fork.Params().AnchorMethods {
+			selectors[fork] = append(selectors[fork], crypto.Keccak256([]byte(method))[:4])
+		}
+	}
+	return selectors
+}()
+
+// hasAnchorSelector returns whether the given calldata calls one of the given anchor
+// methods.
+func hasAnchorSelector(data []byte, selectors [][]byte) bool {
+	for _, selector := range selectors {
+		if bytes.HasPrefix(data, selector) {
+			return true
+		}
+	}
+	return false
+}
+
 // Taiko is a consensus engine used by L2 rollup.
 type Taiko struct {
 	chainConfig    *params.ChainConfig
@@ -46,16 +70,9 @@ type Taiko struct {
 var _ = new(Taiko)
 
 func New(chainConfig *params.ChainConfig) *Taiko {
-	taikoL2AddressPrefix := strings.TrimPrefix(chainConfig.ChainID.String(), "0")
-
 	return &Taiko{
-		chainConfig: chainConfig,
-		taikoL2Address: common.HexToAddress(
-			"0x" +
-				taikoL2AddressPrefix +
-				strings.Repeat("0", common.AddressLength*2-len(taikoL2AddressPrefix)-len(TaikoL2AddressSuffix)) +
-				TaikoL2AddressSuffix,
-		),
+		chainConfig:    chainConfig,
+		taikoL2Address: chainConfig.TaikoL2ContractAddress(),
 	}
 }
 
@@ -229,13 +246,9 @@ func (t *Taiko) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *t
 
 	// Verify anchor transaction
 	if len(body.Transactions) != 0 { // Transactions list might be empty when building empty payload.
-		isAnchor, err := t.ValidateAnchorTx(body.Transactions[0], header)
-		if err != nil {
+		if err := t.VerifyAnchorTransactions(header, body.Transactions); err != nil {
 			return nil, err
 		}
-		if !isAnchor {
-			return nil, ErrAnchorTxNotFound
-		}
 	}
 
 	// Finalize block
@@ -290,7 +303,9 @@ func (t *Taiko) ValidateAnchorTx(tx *types.Transaction, header *types.Header) (b
 		return false, nil
 	}
 
-	if !bytes.HasPrefix(tx.Data(), AnchorSelector) && !bytes.HasPrefix(tx.Data(), AnchorV2Selector) {
+	// Only the anchor methods of the currently active fork are allowed.
+	fork := t.chainConfig.TaikoForkAt(header.Number, header.Time)
+	if !hasAnchorSelector(tx.Data(), anchorSelectors[fork]) {
 		return false, nil
 	}
 
@@ -298,7 +313,7 @@ func (t *Taiko) ValidateAnchorTx(tx *types.Transaction, header *types.Header) (b
 		return false, nil
 	}
 
-	if tx.Gas() != AnchorGasLimit {
+	if tx.Gas() != fork.Params().AnchorGasLimit {
 		return false, nil
 	}
 
@@ -313,7 +328,47 @@ func (t *Taiko) ValidateAnchorTx(tx *types.Transaction, header *types.Header) (b
 		return false, err
 	}
 
-	return strings.EqualFold(addr.String(), GoldenTouchAccount.String()), nil
+	return addr == t.chainConfig.GoldenTouchAccount(), nil
+}
+
+// IsAnchorCall checks if the given transaction calls TaikoL2.anchor or TaikoL2.anchorV2,
+// regardless of its sender and gas settings.
+func (t *Taiko) IsAnchorCall(tx *types.Transaction) bool {
+	if tx.To() == nil || *tx.To() != t.taikoL2Address {
+		return false
+	}
+
+	for fork := params.TaikoGenesis; fork <= params.LatestTaikoFork; fork++ {
+		if hasAnchorSelector(tx.Data(), anchorSelectors[fork]) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyAnchorTransactions checks that the first transaction of the given L2 block
+// transactions list is a valid anchor transaction, and that none of the remaining
+// transactions calls the anchor methods.
+func (t *Taiko) VerifyAnchorTransactions(header *types.Header, txs types.Transactions) error {
+	if len(txs) == 0 {
+		return ErrAnchorTxNotFound
+	}
+
+	isAnchor, err := t.ValidateAnchorTx(txs[0], header)
+	if err != nil {
+		return err
+	}
+	if !isAnchor {
+		return ErrAnchorTxNotFound
+	}
+
+	for i, tx := range txs[1:] {
+		if t.IsAnchorCall(tx) {
+			return fmt.Errorf("%w: index %d, hash %s", ErrUnexpectedAnchorCall, i+1, tx.Hash())
+		}
+	}
+
+	return nil
 }
 
 // APIs returns the RPC APIs this consensus engine provides.
@@ -2,8 +2,8 @@ package taiko_test
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"math/big"
-	"strings"
 	"testing"
 	"time"
 
@@ -28,9 +28,10 @@ var (
 	goldenTouchKey, _   = crypto.HexToECDSA("92954368afd3caa1f3ce3ead0069c1af414054aefe1ef9aeacc1bf426222ce38")
 	testAddr            = crypto.PubkeyToAddress(testKey.PublicKey)
 
-	genesis    *core.Genesis
-	txs        []*types.Transaction
-	testEngine *taiko.Taiko
+	genesis        *core.Genesis
+	txs            []*types.Transaction
+	testEngine     *taiko.Taiko
+	taikoL2Address common.Address
 )
 
 func init() {
@@ -41,14 +42,7 @@ func init() {
 	config.Taiko = true
 	testEngine = taiko.New(config)
 
-	taikoL2AddressPrefix := strings.TrimPrefix(config.ChainID.String(), "0")
-
-	taikoL2Address := common.HexToAddress(
-		"0x" +
-			taikoL2AddressPrefix +
-			strings.Repeat("0", common.AddressLength*2-len(taikoL2AddressPrefix)-len(taiko.TaikoL2AddressSuffix)) +
-			taiko.TaikoL2AddressSuffix,
-	)
+	taikoL2Address = config.TaikoL2ContractAddress()
 
 	genesis = &core.Genesis{
 		Config:     config,
@@ -235,3 +229,73 @@ func TestVerifyHeader(t *testing.T) {
 	})
 	assert.ErrorContains(t, err, "uncles not empty", "VerifyHeader should throw ErrUnclesNotEmpty if uncles is not the empty hash")
 }
+
+func TestVerifyAnchorTransactions(t *testing.T) {
+	header := &types.Header{
+		Number:  common.Big1,
+		BaseFee: new(big.Int).SetUint64(875_000_000),
+	}
+	newAnchorTx := func(key *ecdsa.PrivateKey, data []byte, gas uint64, gasFeeCap *big.Int) *types.Transaction {
+		return types.MustSignNewTx(key, types.LatestSigner(genesis.Config), &types.DynamicFeeTx{
+			Nonce:     0,
+			GasTipCap: common.Big0,
+			GasFeeCap: gasFeeCap,
+			Data:      data,
+			Gas:       gas,
+			To:        &taikoL2Address,
+		})
+	}
+
+	assert.NoError(t, testEngine.VerifyAnchorTransactions(header, txs[:2]))
+	assert.ErrorIs(t, testEngine.VerifyAnchorTransactions(header, nil), taiko.ErrAnchorTxNotFound)
+	assert.ErrorIs(t, testEngine.VerifyAnchorTransactions(header, txs[1:]), taiko.ErrAnchorTxNotFound)
+
+	// Invalid anchor transactions at index 0.
+	for _, tx := range []*types.Transaction{
+		newAnchorTx(testKey, taiko.AnchorSelector, taiko.AnchorGasLimit, header.BaseFee),
+		newAnchorTx(goldenTouchKey, taiko.AnchorSelector, taiko.AnchorGasLimit+1, header.BaseFee),
+		newAnchorTx(goldenTouchKey, taiko.AnchorSelector, taiko.AnchorGasLimit, common.Big1),
+		newAnchorTx(goldenTouchKey, taiko.AnchorV2Selector, taiko.AnchorGasLimit, header.BaseFee),
+	} {
+		assert.ErrorIs(
+			t,
+			testEngine.VerifyAnchorTransactions(header, types.Transactions{tx, txs[1]}),
+			taiko.ErrAnchorTxNotFound,
+		)
+	}
+
+	// Anchor calls after the first transaction.
+	for _, tx := range []*types.Transaction{
+		newAnchorTx(testKey, taiko.AnchorSelector, taiko.AnchorGasLimit, header.BaseFee),
+		newAnchorTx(goldenTouchKey, taiko.AnchorV2Selector, taiko.AnchorGasLimit, header.BaseFee),
+	} {
+		assert.ErrorIs(
+			t,
+			testEngine.VerifyAnchorTransactions(header, types.Transactions{txs[0], txs[1], tx}),
+			taiko.ErrUnexpectedAnchorCall,
+		)
+	}
+}
+
+func TestInsertBlockWithoutAnchor(t *testing.T) {
+	ethService, blocks := newTestBackend(t)
+
+	db := rawdb.NewMemoryDatabase()
+	gblock := genesis.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	chain, _ := core.GenerateChain(genesis.Config, gblock, testEngine, db, len(blocks), func(i int, g *core.BlockGen) {
+		g.OffsetTime(5)
+		g.SetDifficulty(common.Big0)
+
+		// Only the first block, which is already imported, contains an anchor transaction.
+		if i == 0 {
+			g.SetExtra([]byte("test_taiko"))
+			for _, tx := range txs {
+				g.AddTx(tx)
+			}
+		}
+	})
+	assert.Equal(t, blocks[1].Hash(), chain[0].Hash())
+
+	_, err := ethService.BlockChain().InsertChain(chain)
+	assert.ErrorIs(t, err, taiko.ErrAnchorTxNotFound)
+}
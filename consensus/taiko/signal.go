@@ -0,0 +1,53 @@
+package taiko
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignalSlot returns the storage slot of a signal sent by the given app through the
+// SignalService contract of the given chain, which is the
+// `keccak256(abi.encodePacked("SIGNAL", chainId, app, signal))` of
+// SignalService.getSignalSlot.
+func SignalSlot(chainID uint64, app common.Address, signal common.Hash) common.Hash {
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], chainID)
+
+	return crypto.Keccak256Hash([]byte("SIGNAL"), id[:], app.Bytes(), signal.Bytes())
+}
+
+// SignalProof is the Merkle proof of a signal slot of the SignalService contract at
+// a L2 block, together with the L1 origin and the anchor of the block, which tie it
+// to L1.
+type SignalProof struct {
+	Slot        common.Hash     `json:"slot"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	StateRoot   common.Hash     `json:"stateRoot"`
+	L1Origin    *rawdb.L1Origin `json:"l1Origin"`
+	Anchor      *AnchorInfo     `json:"anchor"`
+	Proof       *AccountProof   `json:"proof"`
+}
+
+// AccountProof is the eth_getProof result of the SignalService contract for the
+// signal slot.
+type AccountProof struct {
+	Address      common.Address `json:"address"`
+	AccountProof []string       `json:"accountProof"`
+	Balance      *hexutil.Big   `json:"balance"`
+	CodeHash     common.Hash    `json:"codeHash"`
+	Nonce        hexutil.Uint64 `json:"nonce"`
+	StorageHash  common.Hash    `json:"storageHash"`
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+// StorageProof is the eth_getProof result of a storage slot.
+type StorageProof struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
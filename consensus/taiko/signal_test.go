@@ -0,0 +1,29 @@
+package taiko_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests the signal slot against the packed encoding of SignalService.getSignalSlot:
+// the "SIGNAL" string, the 8 bytes chain ID, the app address and the signal.
+func TestSignalSlot(t *testing.T) {
+	preimage := common.FromHex(
+		"5349474e414c" +
+			"0000000000028c61" +
+			"1670000000000000000000000000000000000001" +
+			"00000000000000000000000000000000000000000000000000000000c0ffee00",
+	)
+	assert.Equal(t,
+		crypto.Keccak256Hash(preimage),
+		taiko.SignalSlot(167009, common.HexToAddress("0x1670000000000000000000000000000000000001"), common.HexToHash("0xc0ffee00")),
+	)
+	assert.NotEqual(t,
+		taiko.SignalSlot(167009, common.Address{}, common.Hash{}),
+		taiko.SignalSlot(167000, common.Address{}, common.Hash{}),
+	)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -66,6 +67,13 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch (header value %x, calculated %x)", header.TxHash, hash)
 	}
+	// CHANGE(taiko): every L2 block must start with a valid TaikoL2 anchor transaction,
+	// and the anchor methods must not be called by any other transaction in the block.
+	if taikoEngine, ok := v.bc.engine.(*taiko.Taiko); ok {
+		if err := taikoEngine.VerifyAnchorTransactions(header, block.Transactions()); err != nil {
+			return err
+		}
+	}
 
 	// Withdrawals are present after the Shanghai fork.
 	if header.WithdrawalsHash != nil {
@@ -248,6 +248,14 @@ type BlockChain struct {
 	txLookupLock  sync.RWMutex
 	txLookupCache *lru.Cache[common.Hash, txLookup]
 
+	// CHANGE(taiko): this mutex synchronizes the L1Origin writes, which read and
+	// update the L1 block indexes and the head L1Origin markers.
+	l1OriginLock sync.Mutex
+
+	// CHANGE(taiko): the transactions skipped in the sealed blocks, which are stored
+	// once the blocks are written into the database.
+	skippedTxsCache *lru.Cache[common.Hash, []*rawdb.SkippedTransaction]
+
 	wg            sync.WaitGroup
 	quit          chan struct{} // shutdown signal, closed in Stop.
 	stopping      atomic.Bool   // false if chain is running, true when stopped
@@ -303,6 +311,9 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		vmConfig:      vmConfig,
 		logger:        vmConfig.Tracer,
 	}
+	// CHANGE(taiko): the transactions skipped in the sealed blocks.
+	bc.skippedTxsCache = lru.NewCache[common.Hash, []*rawdb.SkippedTransaction](skippedTxsCacheLimit)
+
 	var err error
 	bc.hc, err = NewHeaderChain(db, chainConfig, engine, bc.insertStopped)
 	if err != nil {
@@ -333,6 +344,10 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if bc.empty() {
 		rawdb.InitDatabaseFromFreezer(bc.db)
 	}
+	// CHANGE(taiko): index the L1Origins written before the L1 block indexes existed.
+	if bc.chainConfig.Taiko {
+		rawdb.IndexL1Origins(bc.db)
+	}
 	// Load blockchain states from disk
 	if err := bc.loadLastState(); err != nil {
 		return nil, err
@@ -811,6 +826,14 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 	}
 	defer bc.chainmu.Unlock()
 
+	return bc.setHeadBeyondRootLocked(head, time, root, repair)
+}
+
+// CHANGE(taiko): setHeadBeyondRootLocked is setHeadBeyondRoot without acquiring the
+// chain mutex, so that the chain can be rewound atomically with other operations.
+//
+// Note that the caller should hold the chain mutex.
+func (bc *BlockChain) setHeadBeyondRootLocked(head uint64, time uint64, root common.Hash, repair bool) (uint64, error) {
 	var (
 		// Track the block number of the requested root hash
 		rootNumber uint64 // (no root == always 0)
@@ -896,6 +919,8 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 			rawdb.DeleteBody(db, hash, num)
 			rawdb.DeleteReceipts(db, hash, num)
 		}
+		// CHANGE(taiko): remove the transactions skipped in the rewound block.
+		rawdb.DeleteSkippedTransactions(db, hash)
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
 	// If SetHead was only called as a chain reparation method, try to skip
@@ -922,6 +947,10 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 	bc.blockCache.Purge()
 	bc.txLookupCache.Purge()
 
+	// CHANGE(taiko): roll back the L1Origins of the rewound blocks.
+	if newHead := bc.CurrentBlock(); newHead != nil {
+		bc.rewindL1Origins(newHead.Number.Uint64(), newHead.Hash(), newHead.Number.Uint64())
+	}
 	// Clear safe block, finalized block if needed
 	if safe := bc.CurrentSafeBlock(); safe != nil && head < safe.Number.Uint64() {
 		log.Warn("SetHead invalidated safe block")
@@ -1427,6 +1456,8 @@ func (bc *BlockChain) writeBlockWithoutState(block *types.Block, td *big.Int) (e
 	batch := bc.db.NewBatch()
 	rawdb.WriteTd(batch, block.Hash(), block.NumberU64(), td)
 	rawdb.WriteBlock(batch, block)
+	// CHANGE(taiko): store the transactions skipped while sealing the block.
+	bc.writeSkippedTransactions(batch, block.Hash())
 	if err := batch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
@@ -1466,6 +1497,8 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
 	rawdb.WritePreimages(blockBatch, statedb.Preimages())
+	// CHANGE(taiko): store the transactions skipped while sealing the block.
+	bc.writeSkippedTransactions(blockBatch, block.Hash())
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
@@ -2298,6 +2331,11 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 	for _, tx := range diffs {
 		rawdb.DeleteTxLookupEntry(indexesBatch, tx)
 	}
+	// CHANGE(taiko): remove the transactions skipped in the blocks which are no
+	// longer canonical.
+	for _, block := range oldChain {
+		rawdb.DeleteSkippedTransactions(indexesBatch, block.Hash())
+	}
 	// Delete all hash markers that are not part of the new canonical chain.
 	// Because the reorg function does not handle new chain head, all hash
 	// markers greater than or equal to new chain head should be deleted.
@@ -2318,6 +2356,9 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 	// Reset the tx lookup cache to clear stale txlookup cache.
 	bc.txLookupCache.Purge()
 
+	// CHANGE(taiko): roll back the L1Origins of the blocks which are no longer canonical.
+	bc.rewindL1Origins(newHead.NumberU64(), newHead.Hash(), commonBlock.NumberU64())
+
 	// Release the tx-lookup lock after mutation.
 	bc.txLookupLock.Unlock()
 
@@ -1828,6 +1828,9 @@ func testRepairWithScheme(t *testing.T, tt *rewindTest, snapshots bool, scheme s
 	if _, err := chain.InsertChain(canonblocks[tt.commitBlock:]); err != nil {
 		t.Fatalf("Failed to import canonical chain tail: %v", err)
 	}
+	// CHANGE(taiko): write the L1Origins of the canonical blocks.
+	writeTestL1Origins(db, canonblocks, chain.CurrentBlock().Number.Uint64())
+
 	// Force run a freeze cycle
 	type freezer interface {
 		Freeze() error
@@ -1880,6 +1883,8 @@ func testRepairWithScheme(t *testing.T, tt *rewindTest, snapshots bool, scheme s
 	if head := newChain.CurrentBlock(); head.Number.Uint64() != tt.expHeadBlock {
 		t.Errorf("Head block mismatch: have %d, want %d", head.Number, tt.expHeadBlock)
 	}
+	// CHANGE(taiko): the L1Origins should be rolled back together with the chain head.
+	verifyL1Origins(t, db, canonblocks, newChain.CurrentBlock().Number.Uint64())
 	if frozen, err := db.(freezer).Ancients(); err != nil {
 		t.Errorf("Failed to retrieve ancient count: %v\n", err)
 	} else if int(frozen) != tt.expFrozen {
@@ -2031,6 +2031,9 @@ func testSetHeadWithScheme(t *testing.T, tt *rewindTest, snapshots bool, scheme
 	if _, err := chain.InsertChain(canonblocks[tt.commitBlock:]); err != nil {
 		t.Fatalf("Failed to import canonical chain tail: %v", err)
 	}
+	// CHANGE(taiko): write the L1Origins of the canonical blocks.
+	writeTestL1Origins(db, canonblocks, chain.CurrentBlock().Number.Uint64())
+
 	// Reopen the trie database without persisting in-memory dirty nodes.
 	chain.triedb.Close()
 	dbconfig := &triedb.Config{}
@@ -2075,6 +2078,8 @@ func testSetHeadWithScheme(t *testing.T, tt *rewindTest, snapshots bool, scheme
 	if head := chain.CurrentBlock(); head.Number.Uint64() != tt.expHeadBlock {
 		t.Errorf("Head block mismatch: have %d, want %d", head.Number, tt.expHeadBlock)
 	}
+	// CHANGE(taiko): the L1Origins should be rolled back together with the chain head.
+	verifyL1Origins(t, db, canonblocks, chain.CurrentBlock().Number.Uint64())
 	if frozen, err := db.(freezer).Ancients(); err != nil {
 		t.Errorf("Failed to retrieve ancient count: %v\n", err)
 	} else if int(frozen) != tt.expFrozen {
@@ -16,26 +16,29 @@ var _ = (*l1OriginMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (l L1Origin) MarshalJSON() ([]byte, error) {
 	type L1Origin struct {
-		BlockID       *math.HexOrDecimal256 `json:"blockID" gencodec:"required"`
-		L2BlockHash   common.Hash           `json:"l2BlockHash"`
-		L1BlockHeight *math.HexOrDecimal256 `json:"l1BlockHeight" gencodec:"required"`
-		L1BlockHash   common.Hash           `json:"l1BlockHash" gencodec:"required"`
+		BlockID        *math.HexOrDecimal256 `json:"blockID" gencodec:"required"`
+		L2BlockHash    common.Hash           `json:"l2BlockHash"`
+		L1BlockHeight  *math.HexOrDecimal256 `json:"l1BlockHeight"`
+		L1BlockHash    common.Hash           `json:"l1BlockHash"`
+		IsPreconfBlock bool                  `json:"isPreconfBlock" rlp:"optional"`
 	}
 	var enc L1Origin
 	enc.BlockID = (*math.HexOrDecimal256)(l.BlockID)
 	enc.L2BlockHash = l.L2BlockHash
 	enc.L1BlockHeight = (*math.HexOrDecimal256)(l.L1BlockHeight)
 	enc.L1BlockHash = l.L1BlockHash
+	enc.IsPreconfBlock = l.IsPreconfBlock
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (l *L1Origin) UnmarshalJSON(input []byte) error {
 	type L1Origin struct {
-		BlockID       *math.HexOrDecimal256 `json:"blockID" gencodec:"required"`
-		L2BlockHash   *common.Hash          `json:"l2BlockHash"`
-		L1BlockHeight *math.HexOrDecimal256 `json:"l1BlockHeight" gencodec:"required"`
-		L1BlockHash   *common.Hash          `json:"l1BlockHash" gencodec:"required"`
+		BlockID        *math.HexOrDecimal256 `json:"blockID" gencodec:"required"`
+		L2BlockHash    *common.Hash          `json:"l2BlockHash"`
+		L1BlockHeight  *math.HexOrDecimal256 `json:"l1BlockHeight"`
+		L1BlockHash    *common.Hash          `json:"l1BlockHash"`
+		IsPreconfBlock *bool                 `json:"isPreconfBlock" rlp:"optional"`
 	}
 	var dec L1Origin
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -48,13 +51,14 @@ func (l *L1Origin) UnmarshalJSON(input []byte) error {
 	if dec.L2BlockHash != nil {
 		l.L2BlockHash = *dec.L2BlockHash
 	}
-	if dec.L1BlockHeight == nil {
-		return errors.New("missing required field 'l1BlockHeight' for L1Origin")
+	if dec.L1BlockHeight != nil {
+		l.L1BlockHeight = (*big.Int)(dec.L1BlockHeight)
 	}
-	l.L1BlockHeight = (*big.Int)(dec.L1BlockHeight)
-	if dec.L1BlockHash == nil {
-		return errors.New("missing required field 'l1BlockHash' for L1Origin")
+	if dec.L1BlockHash != nil {
+		l.L1BlockHash = *dec.L1BlockHash
+	}
+	if dec.IsPreconfBlock != nil {
+		l.IsPreconfBlock = *dec.IsPreconfBlock
 	}
-	l.L1BlockHash = *dec.L1BlockHash
 	return nil
 }
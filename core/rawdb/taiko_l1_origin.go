@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"slices"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -14,10 +16,25 @@ import (
 
 var (
 	// Database key prefix for L2 block's L1Origin.
-	l1OriginPrefix  = []byte("TKO:L1O")
-	headL1OriginKey = []byte("TKO:LastL1O")
+	l1OriginPrefix         = []byte("TKO:L1O")
+	headL1OriginKey        = []byte("TKO:LastL1O")
+	headPreconfL1OriginKey = []byte("TKO:LastPreconfL1O")
+
+	// Database key prefixes for the L1 block -> L2 block IDs indexes.
+	l1HeightIndexPrefix = []byte("TKO:L1H")
+	l1HashIndexPrefix   = []byte("TKO:L1B")
+
+	// Database key of the lowest L1 block height ever indexed.
+	l1HeightIndexTailKey = []byte("TKO:L1HTail")
+
+	// Database key of the marker of the L1Origins written before the indexes existed
+	// being indexed.
+	l1OriginsIndexedKey = []byte("TKO:IndexedL1O")
 )
 
+// l1OriginIndexBatch is the number of L1Origins indexed per database batch.
+const l1OriginIndexBatch = 10_000
+
 // l1OriginKey calculates the L1Origin key.
 // l1OriginPrefix + l2HeaderHash -> l1OriginKey
 func l1OriginKey(blockID *big.Int) []byte {
@@ -25,14 +42,31 @@ func l1OriginKey(blockID *big.Int) []byte {
 	return append(l1OriginPrefix, data...)
 }
 
+// l1HeightIndexKey calculates the key of L2 block IDs proposed at the given L1 height.
+// l1HeightIndexPrefix + l1BlockHeight -> l1HeightIndexKey
+func l1HeightIndexKey(l1BlockHeight *big.Int) []byte {
+	data, _ := (*math.HexOrDecimal256)(l1BlockHeight).MarshalText()
+	return append(l1HeightIndexPrefix, data...)
+}
+
+// l1HashIndexKey calculates the key of L2 block IDs proposed in the given L1 block.
+// l1HashIndexPrefix + l1BlockHash -> l1HashIndexKey
+func l1HashIndexKey(l1BlockHash common.Hash) []byte {
+	return append(l1HashIndexPrefix, l1BlockHash.Bytes()...)
+}
+
 //go:generate go run github.com/fjl/gencodec -type L1Origin -field-override l1OriginMarshaling -out gen_taiko_l1_origin.go
 
 // L1Origin represents a L1Origin of a L2 block.
 type L1Origin struct {
 	BlockID       *big.Int    `json:"blockID" gencodec:"required"`
 	L2BlockHash   common.Hash `json:"l2BlockHash"`
-	L1BlockHeight *big.Int    `json:"l1BlockHeight" gencodec:"required"`
-	L1BlockHash   common.Hash `json:"l1BlockHash" gencodec:"required"`
+	L1BlockHeight *big.Int    `json:"l1BlockHeight"`
+	L1BlockHash   common.Hash `json:"l1BlockHash"`
+
+	// IsPreconfBlock marks a preconfirmation L2 block, which has not been proposed
+	// on L1 yet, so both L1 block fields are empty.
+	IsPreconfBlock bool `json:"isPreconfBlock" rlp:"optional"`
 }
 
 type l1OriginMarshaling struct {
@@ -63,30 +97,274 @@ func ReadL1Origin(db ethdb.KeyValueReader, blockID *big.Int) (*L1Origin, error)
 	if err := rlp.Decode(bytes.NewReader(data), l1Origin); err != nil {
 		return nil, fmt.Errorf("invalid L1Origin RLP bytes: %w", err)
 	}
+	// The empty L1 block height of a preconfirmation block is decoded as zero.
+	if l1Origin.IsPreconfBlock {
+		l1Origin.L1BlockHeight = nil
+	}
 
 	return l1Origin, nil
 }
 
+// DeleteL1Origin removes the given L2 block's L1Origin from database.
+func DeleteL1Origin(db ethdb.KeyValueWriter, blockID *big.Int) {
+	if err := db.Delete(l1OriginKey(blockID)); err != nil {
+		log.Crit("Failed to delete L1Origin", "err", err)
+	}
+}
+
 // WriteHeadL1Origin stores the given L1Origin as the last L1Origin.
 func WriteHeadL1Origin(db ethdb.KeyValueWriter, blockID *big.Int) {
-	data, _ := (*math.HexOrDecimal256)(blockID).MarshalText()
-	if err := db.Put(headL1OriginKey, data); err != nil {
-		log.Crit("Failed to store head L1Origin", "error", err)
-	}
+	writeL1OriginMarker(db, headL1OriginKey, blockID)
 }
 
 // ReadHeadL1Origin retrieves the last L1Origin from database.
 func ReadHeadL1Origin(db ethdb.KeyValueReader) (*big.Int, error) {
-	data, _ := db.Get(headL1OriginKey)
+	return readL1OriginMarker(db, headL1OriginKey)
+}
+
+// DeleteHeadL1Origin removes the last L1Origin from database.
+func DeleteHeadL1Origin(db ethdb.KeyValueWriter) {
+	deleteL1OriginMarker(db, headL1OriginKey)
+}
+
+// WriteHeadPreconfL1Origin stores the given L1Origin as the last preconfirmation L1Origin.
+func WriteHeadPreconfL1Origin(db ethdb.KeyValueWriter, blockID *big.Int) {
+	writeL1OriginMarker(db, headPreconfL1OriginKey, blockID)
+}
+
+// ReadHeadPreconfL1Origin retrieves the last preconfirmation L1Origin from database.
+func ReadHeadPreconfL1Origin(db ethdb.KeyValueReader) (*big.Int, error) {
+	return readL1OriginMarker(db, headPreconfL1OriginKey)
+}
+
+// DeleteHeadPreconfL1Origin removes the last preconfirmation L1Origin from database.
+func DeleteHeadPreconfL1Origin(db ethdb.KeyValueWriter) {
+	deleteL1OriginMarker(db, headPreconfL1OriginKey)
+}
+
+// writeL1OriginMarker stores the given L2 block ID under the given marker key.
+func writeL1OriginMarker(db ethdb.KeyValueWriter, key []byte, blockID *big.Int) {
+	data, _ := (*math.HexOrDecimal256)(blockID).MarshalText()
+	if err := db.Put(key, data); err != nil {
+		log.Crit("Failed to store head L1Origin", "key", string(key), "error", err)
+	}
+}
+
+// readL1OriginMarker retrieves the L2 block ID stored under the given marker key.
+func readL1OriginMarker(db ethdb.KeyValueReader, key []byte) (*big.Int, error) {
+	data, _ := db.Get(key)
 	if len(data) == 0 {
 		return nil, nil
 	}
 
 	blockID := new(math.HexOrDecimal256)
 	if err := blockID.UnmarshalText(data); err != nil {
-		log.Error("Unmarshal L1Origin unmarshal error", "error", err)
+		log.Error("Unmarshal L1Origin unmarshal error", "key", string(key), "error", err)
 		return nil, fmt.Errorf("invalid L1Origin unmarshal: %w", err)
 	}
 
 	return (*big.Int)(blockID), nil
 }
+
+// deleteL1OriginMarker removes the given marker key from database.
+func deleteL1OriginMarker(db ethdb.KeyValueWriter, key []byte) {
+	if err := db.Delete(key); err != nil {
+		log.Crit("Failed to delete head L1Origin", "key", string(key), "err", err)
+	}
+}
+
+// UpdateL1OriginIndexes removes the block IDs of the removed L1Origins from, then
+// adds the block IDs of the added L1Origins into, the L1 block height and L1 block
+// hash indexes. The indexes are read from the given database, and the changes are
+// written into the given batch. Preconfirmation L1Origins are not indexed.
+func UpdateL1OriginIndexes(db ethdb.KeyValueReader, batch ethdb.KeyValueWriter, removed []*L1Origin, added []*L1Origin) {
+	// Several L1Origins might share the same index key, so the changes are applied
+	// in memory first, since the batch can't be read back.
+	var (
+		keys    []string
+		indexes = make(map[string][]*big.Int)
+	)
+	update := func(l1Origin *L1Origin, fn func([]*big.Int, int, bool) []*big.Int) {
+		if l1Origin.IsPreconfBlock {
+			return
+		}
+		for _, key := range [][]byte{l1HeightIndexKey(l1Origin.L1BlockHeight), l1HashIndexKey(l1Origin.L1BlockHash)} {
+			blockIDs, ok := indexes[string(key)]
+			if !ok {
+				blockIDs = readL2BlockIDs(db, key)
+				keys = append(keys, string(key))
+			}
+			i, found := slices.BinarySearchFunc(blockIDs, l1Origin.BlockID, (*big.Int).Cmp)
+			indexes[string(key)] = fn(blockIDs, i, found)
+		}
+	}
+	for _, l1Origin := range removed {
+		update(l1Origin, func(blockIDs []*big.Int, i int, found bool) []*big.Int {
+			if !found {
+				return blockIDs
+			}
+			return slices.Delete(blockIDs, i, i+1)
+		})
+	}
+	for _, l1Origin := range added {
+		update(l1Origin, func(blockIDs []*big.Int, i int, found bool) []*big.Int {
+			if found {
+				return blockIDs
+			}
+			return slices.Insert(blockIDs, i, l1Origin.BlockID)
+		})
+	}
+	// Move the tail of the L1 block height index down, it's not moved up when
+	// L1Origins are removed, so it's a lower bound of the indexed L1 heights.
+	var (
+		tail    = ReadL1HeightIndexTail(db)
+		newTail = tail
+	)
+	for _, l1Origin := range added {
+		if !l1Origin.IsPreconfBlock && (newTail == nil || l1Origin.L1BlockHeight.Cmp(newTail) < 0) {
+			newTail = l1Origin.L1BlockHeight
+		}
+	}
+	if newTail != tail {
+		writeL1OriginMarker(batch, l1HeightIndexTailKey, newTail)
+	}
+	for _, key := range keys {
+		if blockIDs := indexes[key]; len(blockIDs) > 0 {
+			writeL2BlockIDs(batch, []byte(key), blockIDs)
+		} else if err := batch.Delete([]byte(key)); err != nil {
+			log.Crit("Failed to delete L1Origin index", "err", err)
+		}
+	}
+}
+
+// ReadL1HeightIndexTail retrieves the lowest L1 block height ever indexed, or nil if
+// no L1Origin has been indexed.
+func ReadL1HeightIndexTail(db ethdb.KeyValueReader) *big.Int {
+	tail, err := readL1OriginMarker(db, l1HeightIndexTailKey)
+	if err != nil {
+		return nil
+	}
+	return tail
+}
+
+// IndexL1Origins adds all L1Origins in the database into the L1 block height and
+// L1 block hash indexes, if not done yet, since the L1Origins written before the
+// indexes existed are not indexed.
+func IndexL1Origins(db ethdb.KeyValueStore) {
+	if has, _ := db.Has(l1OriginsIndexedKey); has {
+		return
+	}
+	var (
+		it        = db.NewIterator(l1OriginPrefix, nil)
+		batch     = db.NewBatch()
+		l1Origins []*L1Origin
+		indexed   int
+		start     = time.Now()
+		logged    = time.Now()
+	)
+	defer it.Release()
+
+	flush := func() {
+		UpdateL1OriginIndexes(db, batch, nil, l1Origins)
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to write L1Origin indexes", "err", err)
+		}
+		batch.Reset()
+		indexed += len(l1Origins)
+		l1Origins = l1Origins[:0]
+	}
+	for it.Next() {
+		blockID := new(math.HexOrDecimal256)
+		if err := blockID.UnmarshalText(it.Key()[len(l1OriginPrefix):]); err != nil {
+			continue
+		}
+		l1Origin, err := ReadL1Origin(db, (*big.Int)(blockID))
+		if err != nil || l1Origin == nil {
+			log.Warn("Skipping invalid L1Origin", "blockID", (*big.Int)(blockID), "err", err)
+			continue
+		}
+		if l1Origin.IsPreconfBlock {
+			continue
+		}
+		if l1Origins = append(l1Origins, l1Origin); len(l1Origins) >= l1OriginIndexBatch {
+			flush()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Indexing L1Origins", "indexed", indexed, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("Failed to iterate L1Origins", "err", err)
+	}
+	flush()
+
+	if err := db.Put(l1OriginsIndexedKey, []byte{1}); err != nil {
+		log.Crit("Failed to store L1Origins indexed marker", "err", err)
+	}
+	if indexed > 0 {
+		log.Info("Indexed L1Origins", "indexed", indexed, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
+// ReadL1OriginsByL1Height retrieves the L1Origins of all L2 blocks proposed at the
+// given L1 block height, ordered by the L2 block ID.
+func ReadL1OriginsByL1Height(db ethdb.KeyValueReader, l1BlockHeight *big.Int) ([]*L1Origin, error) {
+	return readIndexedL1Origins(db, l1HeightIndexKey(l1BlockHeight), func(l1Origin *L1Origin) bool {
+		return l1Origin.L1BlockHeight.Cmp(l1BlockHeight) == 0
+	})
+}
+
+// ReadL1OriginsByL1BlockHash retrieves the L1Origins of all L2 blocks proposed in
+// the given L1 block, ordered by the L2 block ID.
+func ReadL1OriginsByL1BlockHash(db ethdb.KeyValueReader, l1BlockHash common.Hash) ([]*L1Origin, error) {
+	return readIndexedL1Origins(db, l1HashIndexKey(l1BlockHash), func(l1Origin *L1Origin) bool {
+		return l1Origin.L1BlockHash == l1BlockHash
+	})
+}
+
+// readIndexedL1Origins retrieves the L1Origins of the L2 block IDs stored under the given
+// index key, the stale entries which no longer match the index are skipped.
+func readIndexedL1Origins(db ethdb.KeyValueReader, key []byte, match func(*L1Origin) bool) ([]*L1Origin, error) {
+	l1Origins := make([]*L1Origin, 0)
+	for _, blockID := range readL2BlockIDs(db, key) {
+		l1Origin, err := ReadL1Origin(db, blockID)
+		if err != nil {
+			return nil, err
+		}
+		if l1Origin == nil || !match(l1Origin) {
+			continue
+		}
+		l1Origins = append(l1Origins, l1Origin)
+	}
+
+	return l1Origins, nil
+}
+
+// readL2BlockIDs retrieves the L2 block IDs stored under the given index key.
+func readL2BlockIDs(db ethdb.KeyValueReader, key []byte) []*big.Int {
+	data, _ := db.Get(key)
+	if len(data) == 0 {
+		return nil
+	}
+
+	var blockIDs []*big.Int
+	if err := rlp.DecodeBytes(data, &blockIDs); err != nil {
+		log.Error("Invalid L1Origin index RLP", "key", common.Bytes2Hex(key), "err", err)
+		return nil
+	}
+
+	return blockIDs
+}
+
+// writeL2BlockIDs stores the L2 block IDs under the given index key.
+func writeL2BlockIDs(db ethdb.KeyValueWriter, key []byte, blockIDs []*big.Int) {
+	data, err := rlp.EncodeToBytes(blockIDs)
+	if err != nil {
+		log.Crit("Failed to encode L1Origin index", "err", err)
+	}
+
+	if err := db.Put(key, data); err != nil {
+		log.Crit("Failed to store L1Origin index", "err", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -48,6 +49,64 @@ func TestL1Origin(t *testing.T) {
 	assert.Equal(t, testL1Origin.L1BlockHash, l1Origin.L1BlockHash)
 }
 
+func TestPreconfL1Origin(t *testing.T) {
+	db := NewMemoryDatabase()
+	testL1Origin := &L1Origin{
+		BlockID:        randomBigInt(),
+		L2BlockHash:    randomHash(),
+		IsPreconfBlock: true,
+	}
+	WriteL1Origin(db, testL1Origin.BlockID, testL1Origin)
+	UpdateL1OriginIndexes(db, db, nil, []*L1Origin{testL1Origin})
+	WriteHeadPreconfL1Origin(db, testL1Origin.BlockID)
+
+	l1Origin, err := ReadL1Origin(db, testL1Origin.BlockID)
+	require.Nil(t, err)
+	require.Equal(t, testL1Origin, l1Origin)
+
+	// Preconfirmation blocks are not indexed by their empty L1 block fields.
+	l1Origins, err := ReadL1OriginsByL1BlockHash(db, common.Hash{})
+	require.Nil(t, err)
+	require.Empty(t, l1Origins)
+
+	blockID, err := ReadHeadPreconfL1Origin(db)
+	require.Nil(t, err)
+	assert.Equal(t, testL1Origin.BlockID, blockID)
+
+	headL1OriginID, err := ReadHeadL1Origin(db)
+	require.Nil(t, err)
+	require.Nil(t, headL1OriginID)
+
+	DeleteHeadPreconfL1Origin(db)
+	blockID, err = ReadHeadPreconfL1Origin(db)
+	require.Nil(t, err)
+	require.Nil(t, blockID)
+}
+
+// Tests that the L1Origins stored before the preconfirmation flag was added can
+// still be decoded.
+func TestLegacyL1Origin(t *testing.T) {
+	db := NewMemoryDatabase()
+	testL1Origin := &L1Origin{
+		BlockID:       randomBigInt(),
+		L2BlockHash:   randomHash(),
+		L1BlockHeight: randomBigInt(),
+		L1BlockHash:   randomHash(),
+	}
+	data, err := rlp.EncodeToBytes([]interface{}{
+		testL1Origin.BlockID,
+		testL1Origin.L2BlockHash,
+		testL1Origin.L1BlockHeight,
+		testL1Origin.L1BlockHash,
+	})
+	require.Nil(t, err)
+	require.Nil(t, db.Put(l1OriginKey(testL1Origin.BlockID), data))
+
+	l1Origin, err := ReadL1Origin(db, testL1Origin.BlockID)
+	require.Nil(t, err)
+	require.Equal(t, testL1Origin, l1Origin)
+}
+
 func TestHeadL1Origin(t *testing.T) {
 	db := NewMemoryDatabase()
 	testBlockID := randomBigInt()
@@ -57,3 +116,109 @@ func TestHeadL1Origin(t *testing.T) {
 	require.NotNil(t, blockID)
 	assert.Equal(t, testBlockID, blockID)
 }
+
+func TestL1OriginIndexes(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		l1BlockHeight = randomBigInt()
+		l1BlockHash   = randomHash()
+		l1Origins     []*L1Origin
+	)
+	for _, blockID := range []int64{3, 1, 2} {
+		l1Origin := &L1Origin{
+			BlockID:       big.NewInt(blockID),
+			L2BlockHash:   randomHash(),
+			L1BlockHeight: l1BlockHeight,
+			L1BlockHash:   l1BlockHash,
+		}
+		WriteL1Origin(db, l1Origin.BlockID, l1Origin)
+		UpdateL1OriginIndexes(db, db, nil, []*L1Origin{l1Origin})
+		UpdateL1OriginIndexes(db, db, nil, []*L1Origin{l1Origin}) // Writing twice should not duplicate the index.
+		l1Origins = append(l1Origins, l1Origin)
+	}
+
+	byHeight, err := ReadL1OriginsByL1Height(db, l1BlockHeight)
+	require.Nil(t, err)
+	require.Equal(t, []*L1Origin{l1Origins[1], l1Origins[2], l1Origins[0]}, byHeight)
+
+	byHash, err := ReadL1OriginsByL1BlockHash(db, l1BlockHash)
+	require.Nil(t, err)
+	require.Equal(t, byHeight, byHash)
+
+	// Replace the L1Origin of block 2 with a different L1 block.
+	replaced := &L1Origin{
+		BlockID:       l1Origins[2].BlockID,
+		L2BlockHash:   randomHash(),
+		L1BlockHeight: new(big.Int).Add(l1BlockHeight, common.Big1),
+		L1BlockHash:   randomHash(),
+	}
+	WriteL1Origin(db, replaced.BlockID, replaced)
+	UpdateL1OriginIndexes(db, db, []*L1Origin{l1Origins[2]}, []*L1Origin{replaced})
+
+	byHeight, err = ReadL1OriginsByL1Height(db, l1BlockHeight)
+	require.Nil(t, err)
+	require.Equal(t, []*L1Origin{l1Origins[1], l1Origins[0]}, byHeight)
+
+	byHash, err = ReadL1OriginsByL1BlockHash(db, replaced.L1BlockHash)
+	require.Nil(t, err)
+	require.Equal(t, []*L1Origin{replaced}, byHash)
+
+	// Stale index entries are skipped even if they are not deleted.
+	WriteL1Origin(db, l1Origins[0].BlockID, &L1Origin{
+		BlockID:       l1Origins[0].BlockID,
+		L2BlockHash:   randomHash(),
+		L1BlockHeight: replaced.L1BlockHeight,
+		L1BlockHash:   replaced.L1BlockHash,
+	})
+	byHash, err = ReadL1OriginsByL1BlockHash(db, l1BlockHash)
+	require.Nil(t, err)
+	require.Equal(t, []*L1Origin{l1Origins[1]}, byHash)
+
+	// Unknown L1 blocks.
+	byHash, err = ReadL1OriginsByL1BlockHash(db, randomHash())
+	require.Nil(t, err)
+	require.Empty(t, byHash)
+}
+
+func TestIndexL1Origins(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	// L1Origins written before the indexes existed.
+	var (
+		l1BlockHash = randomHash()
+		l1Origins   []*L1Origin
+	)
+	for blockID := int64(1); blockID <= 3; blockID++ {
+		l1Origin := &L1Origin{
+			BlockID:       big.NewInt(blockID),
+			L2BlockHash:   randomHash(),
+			L1BlockHeight: big.NewInt(10),
+			L1BlockHash:   l1BlockHash,
+		}
+		WriteL1Origin(db, l1Origin.BlockID, l1Origin)
+		l1Origins = append(l1Origins, l1Origin)
+	}
+	WriteL1Origin(db, big.NewInt(4), &L1Origin{BlockID: big.NewInt(4), L2BlockHash: randomHash(), IsPreconfBlock: true})
+	WriteHeadL1Origin(db, big.NewInt(3))
+
+	byHeight, err := ReadL1OriginsByL1Height(db, big.NewInt(10))
+	require.Nil(t, err)
+	require.Empty(t, byHeight)
+
+	IndexL1Origins(db)
+	byHeight, err = ReadL1OriginsByL1Height(db, big.NewInt(10))
+	require.Nil(t, err)
+	require.Equal(t, l1Origins, byHeight)
+
+	byHash, err := ReadL1OriginsByL1BlockHash(db, l1BlockHash)
+	require.Nil(t, err)
+	require.Equal(t, l1Origins, byHash)
+
+	// The L1Origins are only indexed once.
+	UpdateL1OriginIndexes(db, db, l1Origins[:1], nil)
+	IndexL1Origins(db)
+	byHeight, err = ReadL1OriginsByL1Height(db, big.NewInt(10))
+	require.Nil(t, err)
+	require.Equal(t, l1Origins[1:], byHeight)
+}
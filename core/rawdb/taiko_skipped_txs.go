@@ -0,0 +1,61 @@
+package rawdb
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Database key prefix for the transactions skipped when sealing a L2 block.
+var skippedTxsPrefix = []byte("TKO:SKP")
+
+// skippedTxsKey calculates the key of the transactions skipped in the given L2 block.
+// skippedTxsPrefix + l2BlockHash -> skippedTxsKey
+func skippedTxsKey(blockHash common.Hash) []byte {
+	return append(skippedTxsPrefix, blockHash.Bytes()...)
+}
+
+// SkippedTransaction is a transaction proposed in the transactions list of a L2
+// block, which was left out of the sealed block.
+type SkippedTransaction struct {
+	Hash   common.Hash `json:"hash"`
+	Reason string      `json:"reason"`
+}
+
+// WriteSkippedTransactions stores the transactions skipped in the given L2 block.
+func WriteSkippedTransactions(db ethdb.KeyValueWriter, blockHash common.Hash, txs []*SkippedTransaction) {
+	data, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		log.Crit("Failed to encode skipped transactions", "err", err)
+	}
+
+	if err := db.Put(skippedTxsKey(blockHash), data); err != nil {
+		log.Crit("Failed to store skipped transactions", "err", err)
+	}
+}
+
+// ReadSkippedTransactions retrieves the transactions skipped in the given L2 block,
+// nil is returned if none was recorded.
+func ReadSkippedTransactions(db ethdb.KeyValueReader, blockHash common.Hash) ([]*SkippedTransaction, error) {
+	data, _ := db.Get(skippedTxsKey(blockHash))
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var txs []*SkippedTransaction
+	if err := rlp.DecodeBytes(data, &txs); err != nil {
+		return nil, fmt.Errorf("invalid skipped transactions RLP bytes: %w", err)
+	}
+
+	return txs, nil
+}
+
+// DeleteSkippedTransactions removes the transactions skipped in the given L2 block.
+func DeleteSkippedTransactions(db ethdb.KeyValueWriter, blockHash common.Hash) {
+	if err := db.Delete(skippedTxsKey(blockHash)); err != nil {
+		log.Crit("Failed to delete skipped transactions", "err", err)
+	}
+}
@@ -0,0 +1,31 @@
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkippedTransactions(t *testing.T) {
+	var (
+		db        = NewMemoryDatabase()
+		blockHash = randomHash()
+		skipped   = []*SkippedTransaction{
+			{Hash: randomHash(), Reason: "invalid sender"},
+			{Hash: randomHash(), Reason: "nonce too high"},
+		}
+	)
+	txs, err := ReadSkippedTransactions(db, blockHash)
+	require.Nil(t, err)
+	require.Nil(t, txs)
+
+	WriteSkippedTransactions(db, blockHash, skipped)
+	txs, err = ReadSkippedTransactions(db, blockHash)
+	require.Nil(t, err)
+	require.Equal(t, skipped, txs)
+
+	DeleteSkippedTransactions(db, blockHash)
+	txs, err = ReadSkippedTransactions(db, blockHash)
+	require.Nil(t, err)
+	require.Nil(t, txs)
+}
@@ -22,10 +22,12 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -81,6 +83,16 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	if p.config.IsPrague(block.Number(), block.Time()) {
 		ProcessParentBlockHash(block.ParentHash(), vmenv, statedb)
 	}
+	// CHANGE(taiko): check the base fee of Ontake blocks against the gas state of
+	// TaikoL2, read without the tracer before the anchor transaction updates it. The
+	// mismatches are only logged, until the calculation is checked against the
+	// protocol test vectors and the mainnet blocks.
+	if taikoEngine, ok := p.chain.engine.(*taiko.Taiko); ok && p.config.IsOntake(header.Number, header.Time) {
+		evm := vm.NewEVM(context, vm.TxContext{}, statedb, p.config, vm.Config{})
+		if err := taikoEngine.VerifyBaseFee(header, block.Transactions(), evm); err != nil {
+			log.Warn("Failed to verify the base fee of the block", "number", header.Number, "hash", block.Hash(), "err", err)
+		}
+	}
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
 		// CHANGE(taiko): mark the first transaction as anchor transaction.
@@ -93,9 +105,8 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		if err != nil {
 			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 		}
-		if p.config.IsOntake(block.Number()) {
-			msg.BasefeeSharingPctg = DecodeOntakeExtraData(header.Extra)
-		}
+		// CHANGE(taiko): decode the basefeeSharingPctg config from the extradata.
+		msg.BasefeeSharingPctg = p.config.TaikoParamsAt(header.Number, header.Time).BasefeeSharingPctg(header.Extra)
 		statedb.SetTxContext(tx.Hash(), i)
 
 		receipt, err := ApplyTransactionWithEVM(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
@@ -207,10 +218,8 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 		return nil, err
 	}
 	// CHANGE(taiko): decode the basefeeSharingPctg config from the extradata, and
-	// add it to the Message, if its an ontake block.
-	if config.IsOntake(header.Number) {
-		msg.BasefeeSharingPctg = DecodeOntakeExtraData(header.Extra)
-	}
+	// add it to the Message, if the active Taiko fork shares the base fee.
+	msg.BasefeeSharingPctg = config.TaikoParamsAt(header.Number, header.Time).BasefeeSharingPctg(header.Extra)
 	// Create a new context to be used in the EVM environment
 	blockContext := NewEVMBlockContext(header, bc, author)
 	txContext := NewEVMTxContext(msg)
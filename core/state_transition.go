@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"math"
 	"math/big"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	cmath "github.com/ethereum/go-ethereum/common/math"
@@ -490,13 +489,8 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 
 		// CHANGE(taiko): basefee is not burnt, but sent to a treasury and block.coinbase instead.
 		if st.evm.ChainConfig().Taiko && st.evm.Context.BaseFee != nil && !st.msg.IsAnchor {
-			totalFee := new(big.Int).Mul(st.evm.Context.BaseFee, new(big.Int).SetUint64(st.gasUsed()))
-			feeCoinbase := new(big.Int).Div(
-				new(big.Int).Mul(totalFee, new(big.Int).SetUint64(uint64(st.msg.BasefeeSharingPctg))),
-				new(big.Int).SetUint64(100),
-			)
-			feeTreasury := new(big.Int).Sub(totalFee, feeCoinbase)
-			st.state.AddBalance(st.getTreasuryAddress(), uint256.MustFromBig(feeTreasury), tracing.BalanceIncreaseTreasury)
+			feeTreasury, feeCoinbase := SplitTaikoBaseFee(st.evm.Context.BaseFee, st.gasUsed(), st.msg.BasefeeSharingPctg)
+			st.state.AddBalance(st.evm.ChainConfig().TaikoTreasuryAddress(), uint256.MustFromBig(feeTreasury), tracing.BalanceIncreaseTreasury)
 			st.state.AddBalance(st.evm.Context.Coinbase, uint256.MustFromBig(feeCoinbase), tracing.BalanceIncreaseBaseFeeSharing)
 		}
 		// add the coinbase to the witness iff the fee is greater than 0
@@ -552,23 +546,3 @@ func (st *StateTransition) gasUsed() uint64 {
 func (st *StateTransition) blobGasUsed() uint64 {
 	return uint64(len(st.msg.BlobHashes) * params.BlobTxBlobGasPerBlob)
 }
-
-// CHANGE(taiko): returns the treasury address based on chain ID.
-func (st *StateTransition) getTreasuryAddress() common.Address {
-	var (
-		prefix = st.evm.ChainConfig().ChainID.String()
-		suffix = "10001"
-	)
-	return common.HexToAddress(
-		"0x" +
-			prefix +
-			strings.Repeat("0", common.AddressLength*2-len(prefix)-len(suffix)) +
-			suffix,
-	)
-}
-
-// DecodeOntakeExtraData decodes an ontake block's extradata, returns basefeeSharingPctg configurations,
-// the corresponding enocding function in protocol is `LibProposing._encodeGasConfigs`.
-func DecodeOntakeExtraData(extradata []byte) uint8 {
-	return uint8(new(big.Int).SetBytes(extradata).Uint64())
-}
@@ -21,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -62,6 +63,11 @@ func ExecuteStateless(config *params.ChainConfig, block *types.Block, witness *s
 		headerCache: lru.NewCache[common.Hash, *types.Header](256),
 		engine:      beacon.New(ethash.NewFaker()),
 	}
+	// CHANGE(taiko): execute the L2 blocks with the Taiko engine, which verifies the
+	// base fee against TaikoL2 and credits the withdrawals in wei.
+	if config.Taiko {
+		chain.engine = taiko.New(config)
+	}
 	processor := NewStateProcessor(config, chain)
 	validator := NewBlockValidator(config, nil) // No chain, we only validate the state, not the block
 
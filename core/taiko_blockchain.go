@@ -0,0 +1,346 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errHeadL1OriginNotFound is returned if the head L1Origin is not found in database.
+var errHeadL1OriginNotFound = errors.New("head L1Origin not found")
+
+// skippedTxsCacheLimit is the number of sealed blocks whose skipped transactions are
+// kept in memory until the blocks are written.
+const skippedTxsCacheLimit = 64
+
+// WriteL1Origin stores the given L1Origin of a L2 block together with its L1 block
+// indexes, and updates the head L1Origin, or the head preconfirmation L1Origin if the
+// L2 block has not been proposed on L1 yet.
+func (bc *BlockChain) WriteL1Origin(l1Origin *rawdb.L1Origin) {
+	bc.l1OriginLock.Lock()
+	defer bc.l1OriginLock.Unlock()
+
+	batch := bc.db.NewBatch()
+
+	// Remove the replaced L1Origin from the L1 block indexes, if any.
+	var removed []*rawdb.L1Origin
+	prevL1Origin, err := rawdb.ReadL1Origin(bc.db, l1Origin.BlockID)
+	if err != nil {
+		log.Error("Failed to read previous L1Origin", "blockID", l1Origin.BlockID, "err", err)
+	} else if prevL1Origin != nil {
+		removed = append(removed, prevL1Origin)
+	}
+	rawdb.WriteL1Origin(batch, l1Origin.BlockID, l1Origin)
+	rawdb.UpdateL1OriginIndexes(bc.db, batch, removed, []*rawdb.L1Origin{l1Origin})
+
+	if l1Origin.IsPreconfBlock {
+		rawdb.WriteHeadPreconfL1Origin(batch, l1Origin.BlockID)
+	} else {
+		rawdb.WriteHeadL1Origin(batch, l1Origin.BlockID)
+
+		// All preconfirmation blocks up to this L2 block have been proposed on L1 now.
+		headPreconfID, err := rawdb.ReadHeadPreconfL1Origin(bc.db)
+		if err != nil {
+			log.Error("Failed to read head preconfirmation L1Origin", "err", err)
+		} else if headPreconfID != nil && headPreconfID.Cmp(l1Origin.BlockID) <= 0 {
+			rawdb.DeleteHeadPreconfL1Origin(batch)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write L1Origin", "err", err)
+	}
+}
+
+// ConfirmL1Origin attaches the given L1Origin to an already inserted canonical L2
+// block, which is usually a preconfirmation block that has been proposed on L1 now.
+func (bc *BlockChain) ConfirmL1Origin(l1Origin *rawdb.L1Origin) (*rawdb.L1Origin, error) {
+	if l1Origin.BlockID == nil {
+		return nil, errors.New("missing L2 block ID")
+	}
+	if l1Origin.IsPreconfBlock || l1Origin.L1BlockHeight == nil {
+		return nil, fmt.Errorf("L1Origin of L2 block %d is not proposed on L1", l1Origin.BlockID)
+	}
+
+	if !bc.chainmu.TryLock() {
+		return nil, errChainStopped
+	}
+	defer bc.chainmu.Unlock()
+
+	hash := rawdb.ReadCanonicalHash(bc.db, l1Origin.BlockID.Uint64())
+	if hash == (common.Hash{}) {
+		return nil, fmt.Errorf("canonical L2 block %d not found", l1Origin.BlockID)
+	}
+	if l1Origin.L2BlockHash == (common.Hash{}) {
+		l1Origin.L2BlockHash = hash
+	}
+	if l1Origin.L2BlockHash != hash {
+		return nil, fmt.Errorf("L2 block hash mismatch: have %s, canonical %s", l1Origin.L2BlockHash, hash)
+	}
+	bc.WriteL1Origin(l1Origin)
+
+	return l1Origin, nil
+}
+
+// rewindL1Origins treats the L1Origins as chain-derived data, and rolls them back
+// after the canonical chain has been rewound or reorged to the new head with the
+// given number and hash:
+//  1. all L1Origins above the new head are deleted, since their L2 blocks are no
+//     longer canonical.
+//  2. the L1Origins between the given common ancestor and the new head which do not
+//     point to the canonical L2 blocks are deleted.
+//  3. the head L1Origin and the head preconfirmation L1Origin are moved back to the
+//     highest remaining ones.
+//
+// No L1Origin is written above the head L1Origins, so the scans stop there.
+//
+// Note that the caller should hold the chain mutex.
+func (bc *BlockChain) rewindL1Origins(number uint64, hash common.Hash, ancestor uint64) {
+	bc.l1OriginLock.Lock()
+	defer bc.l1OriginLock.Unlock()
+
+	headL1OriginID, err := rawdb.ReadHeadL1Origin(bc.db)
+	if err != nil {
+		log.Error("Failed to read head L1Origin", "err", err)
+		return
+	}
+	headPreconfID, err := rawdb.ReadHeadPreconfL1Origin(bc.db)
+	if err != nil {
+		log.Error("Failed to read head preconfirmation L1Origin", "err", err)
+		return
+	}
+	// No L1Origin has been written, nothing to roll back.
+	if headL1OriginID == nil && headPreconfID == nil {
+		return
+	}
+
+	var (
+		upper   uint64
+		deleted []*rawdb.L1Origin
+		removed = make(map[uint64]bool)
+	)
+	if headL1OriginID != nil {
+		upper = max(upper, headL1OriginID.Uint64())
+	}
+	if headPreconfID != nil {
+		upper = max(upper, headPreconfID.Uint64())
+	}
+	for n := upper; n > ancestor; n-- {
+		l1Origin, err := rawdb.ReadL1Origin(bc.db, new(big.Int).SetUint64(n))
+		if err != nil {
+			log.Error("Failed to read L1Origin", "blockID", n, "err", err)
+			continue
+		}
+		if l1Origin == nil {
+			continue
+		}
+		if n <= number {
+			canonical := hash
+			if n != number {
+				canonical = rawdb.ReadCanonicalHash(bc.db, n)
+			}
+			if l1Origin.L2BlockHash == canonical {
+				continue
+			}
+		}
+		deleted = append(deleted, l1Origin)
+		removed[n] = true
+	}
+	// The deletions and the head markers are written in one batch, so the markers
+	// never point to deleted L1Origins. The database is read before the batch is
+	// written, so the deleted L1Origins are skipped explicitly.
+	batch := bc.db.NewBatch()
+	bc.deleteL1Origins(batch, deleted)
+
+	// Move the head L1Origin back to the highest remaining L1Origin proposed on L1.
+	var confirmed uint64
+	if headL1OriginID != nil {
+		confirmed = headL1OriginID.Uint64()
+		if removed[confirmed] || confirmed > number {
+			confirmed = 0
+			l1Origin, err := bc.lastProposedL1Origin(number, nil, removed)
+			if err != nil {
+				log.Error("Failed to find the head L1Origin", "err", err)
+			} else if l1Origin != nil {
+				confirmed = l1Origin.BlockID.Uint64()
+			}
+			if confirmed == 0 {
+				rawdb.DeleteHeadL1Origin(batch)
+			} else {
+				rawdb.WriteHeadL1Origin(batch, new(big.Int).SetUint64(confirmed))
+			}
+		}
+	}
+	// Move the head preconfirmation L1Origin back, the preconfirmation blocks are the
+	// L2 blocks right above the confirmed ones, so the new head is the new chain head
+	// if it's still a preconfirmation block.
+	if headPreconfID != nil && headPreconfID.Uint64() > number {
+		l1Origin, err := rawdb.ReadL1Origin(bc.db, new(big.Int).SetUint64(number))
+		if err != nil {
+			log.Error("Failed to read L1Origin", "blockID", number, "err", err)
+		}
+		if l1Origin != nil && !removed[number] && l1Origin.IsPreconfBlock && number > confirmed {
+			rawdb.WriteHeadPreconfL1Origin(batch, l1Origin.BlockID)
+		} else {
+			rawdb.DeleteHeadPreconfL1Origin(batch)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to rewind L1Origins", "err", err)
+	}
+	if len(deleted) > 0 {
+		log.Info("Rewound L1Origins", "number", number, "hash", hash, "deleted", len(deleted))
+	}
+}
+
+// lastProposedL1Origin returns the L1Origin of the highest L2 block at or below the
+// given block ID which has been proposed on L1, at or below the given L1 height if
+// any, skipping the given removed ones, or nil if not found. It walks the L2 blocks
+// downwards over the preconfirmation blocks and the ones without L1Origins, e.g. the
+// beacon synced ones, until the lowest L2 block in the L1 block height index.
+func (bc *BlockChain) lastProposedL1Origin(blockID uint64, l1Height *big.Int, removed map[uint64]bool) (*rawdb.L1Origin, error) {
+	tail := rawdb.ReadL1HeightIndexTail(bc.db)
+	if tail == nil {
+		return nil, nil
+	}
+	// The L1 block heights of the L2 blocks are monotonic, so the lowest indexed L2
+	// block is proposed at the tail. The tail is not moved up when L1Origins are
+	// removed though, the walk goes down to the genesis then.
+	var lowest uint64
+	l1Origins, err := rawdb.ReadL1OriginsByL1Height(bc.db, tail)
+	if err != nil {
+		return nil, err
+	}
+	if len(l1Origins) > 0 {
+		lowest = l1Origins[0].BlockID.Uint64()
+	}
+	for n := blockID; n >= lowest; n-- {
+		l1Origin, err := rawdb.ReadL1Origin(bc.db, new(big.Int).SetUint64(n))
+		if err != nil {
+			return nil, err
+		}
+		if l1Origin != nil && !l1Origin.IsPreconfBlock && !removed[n] &&
+			(l1Height == nil || l1Origin.L1BlockHeight.Cmp(l1Height) <= 0) {
+			return l1Origin, nil
+		}
+		if n == 0 {
+			break
+		}
+	}
+	return nil, nil
+}
+
+// deleteL1Origins deletes the given L1Origins and their L1 block indexes into the
+// given batch.
+func (bc *BlockChain) deleteL1Origins(batch ethdb.KeyValueWriter, l1Origins []*rawdb.L1Origin) {
+	if len(l1Origins) == 0 {
+		return
+	}
+	rawdb.UpdateL1OriginIndexes(bc.db, batch, l1Origins, nil)
+	for _, l1Origin := range l1Origins {
+		rawdb.DeleteL1Origin(batch, l1Origin.BlockID)
+	}
+}
+
+// RewindToL1Height rewinds the canonical chain to the last L2 block whose L1Origin is
+// at or below the given L1 block height, the L1Origins above it are rolled back together
+// with the chain head, including all preconfirmation blocks, also if the chain head is
+// still below that L2 block. It returns the new head L1Origin.
+func (bc *BlockChain) RewindToL1Height(l1Height *big.Int) (*rawdb.L1Origin, error) {
+	if !bc.chainmu.TryLock() {
+		return nil, errChainStopped
+	}
+	l1Origin, rewound, err := bc.rewindToL1Height(l1Height)
+	bc.chainmu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Send chain head event to update the transaction pool
+	if rewound {
+		header := bc.CurrentBlock()
+		if block := bc.GetBlock(header.Hash(), header.Number.Uint64()); block != nil {
+			bc.chainHeadFeed.Send(ChainHeadEvent{Block: block})
+		}
+	}
+	return l1Origin, nil
+}
+
+// rewindToL1Height implements RewindToL1Height, and reports whether the chain head
+// has been rewound.
+//
+// Note that the caller should hold the chain mutex.
+func (bc *BlockChain) rewindToL1Height(l1Height *big.Int) (*rawdb.L1Origin, bool, error) {
+	headL1OriginID, err := rawdb.ReadHeadL1Origin(bc.db)
+	if err != nil {
+		return nil, false, err
+	}
+	if headL1OriginID == nil {
+		return nil, false, errHeadL1OriginNotFound
+	}
+	headL1Origin, err := rawdb.ReadL1Origin(bc.db, headL1OriginID)
+	if err != nil {
+		return nil, false, err
+	}
+	if headL1Origin == nil || headL1Origin.IsPreconfBlock {
+		return nil, false, errHeadL1OriginNotFound
+	}
+
+	// Find the last L1Origin at or below the given L1 height, no L1Origin proposed
+	// on L1 is above the head L1Origin.
+	target, err := bc.lastProposedL1Origin(headL1OriginID.Uint64(), l1Height, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if target == nil {
+		return nil, false, fmt.Errorf("no L1Origin found at or below L1 height %d", l1Height)
+	}
+
+	var (
+		number  = target.BlockID.Uint64()
+		rewound bool
+	)
+	if number < bc.CurrentBlock().Number.Uint64() {
+		log.Warn("Rewinding blockchain to L1 height", "l1Height", l1Height, "target", number)
+		if _, err := bc.setHeadBeyondRootLocked(number, 0, common.Hash{}, false); err != nil {
+			return nil, false, err
+		}
+		rewound = true
+	}
+	// Roll back the L1Origins above the target, the chain head might be below it.
+	bc.rewindL1Origins(number, target.L2BlockHash, number)
+
+	if headL1OriginID, err = rawdb.ReadHeadL1Origin(bc.db); err != nil {
+		return nil, false, err
+	}
+	if headL1OriginID == nil {
+		return nil, false, errHeadL1OriginNotFound
+	}
+	l1Origin, err := rawdb.ReadL1Origin(bc.db, headL1OriginID)
+	return l1Origin, rewound, err
+}
+
+// AddSkippedTransactions records the proposed transactions which were left out of
+// the given sealed L2 block. They are stored once the block is written into the
+// database, so the sealed blocks which are never inserted leave nothing behind.
+func (bc *BlockChain) AddSkippedTransactions(block *types.Block, txs []*rawdb.SkippedTransaction) {
+	if bc.HasBlock(block.Hash(), block.NumberU64()) {
+		rawdb.WriteSkippedTransactions(bc.db, block.Hash(), txs)
+		return
+	}
+	bc.skippedTxsCache.Add(block.Hash(), txs)
+}
+
+// writeSkippedTransactions stores the transactions skipped in the given L2 block
+// into the given batch, if any were recorded.
+func (bc *BlockChain) writeSkippedTransactions(batch ethdb.KeyValueWriter, blockHash common.Hash) {
+	if txs, ok := bc.skippedTxsCache.Get(blockHash); ok {
+		rawdb.WriteSkippedTransactions(batch, blockHash, txs)
+		bc.skippedTxsCache.Remove(blockHash)
+	}
+}
@@ -0,0 +1,576 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testL1Origin returns the L1Origin of the given L2 block used in tests.
+func testL1Origin(block *types.Block) *rawdb.L1Origin {
+	return &rawdb.L1Origin{
+		BlockID:       block.Number(),
+		L2BlockHash:   block.Hash(),
+		L1BlockHeight: new(big.Int).Add(block.Number(), big.NewInt(1000)),
+		L1BlockHash:   common.BigToHash(block.Number()),
+	}
+}
+
+// writeTestL1Origins writes the L1Origins of the given blocks up to the given head,
+// and marks the last one as the head L1Origin.
+func writeTestL1Origins(db ethdb.KeyValueStore, blocks types.Blocks, head uint64) {
+	for _, block := range blocks {
+		if block.NumberU64() > head {
+			break
+		}
+		l1Origin := testL1Origin(block)
+		rawdb.WriteL1Origin(db, l1Origin.BlockID, l1Origin)
+		rawdb.UpdateL1OriginIndexes(db, db, nil, []*rawdb.L1Origin{l1Origin})
+		rawdb.WriteHeadL1Origin(db, l1Origin.BlockID)
+	}
+}
+
+// verifyL1Origins checks that only the L1Origins of the given blocks up to the given
+// head are retained, and the head L1Origin points to the given head.
+func verifyL1Origins(t *testing.T, db ethdb.KeyValueStore, blocks types.Blocks, head uint64) {
+	t.Helper()
+
+	for _, block := range blocks {
+		l1Origin, err := rawdb.ReadL1Origin(db, block.Number())
+		if err != nil {
+			t.Fatalf("Failed to read L1Origin #%d: %v", block.NumberU64(), err)
+		}
+		indexed, err := rawdb.ReadL1OriginsByL1BlockHash(db, testL1Origin(block).L1BlockHash)
+		if err != nil {
+			t.Fatalf("Failed to read indexed L1Origins #%d: %v", block.NumberU64(), err)
+		}
+		if block.NumberU64() <= head {
+			if l1Origin == nil || l1Origin.L2BlockHash != block.Hash() {
+				t.Errorf("L1Origin #%d mismatch: have %v, want %x", block.NumberU64(), l1Origin, block.Hash())
+			}
+			if len(indexed) != 1 {
+				t.Errorf("Indexed L1Origins #%d mismatch: have %d, want 1", block.NumberU64(), len(indexed))
+			}
+		} else {
+			if l1Origin != nil {
+				t.Errorf("L1Origin #%d not rolled back: %v", block.NumberU64(), l1Origin)
+			}
+			if len(indexed) != 0 {
+				t.Errorf("Indexed L1Origins #%d not rolled back: have %d", block.NumberU64(), len(indexed))
+			}
+		}
+	}
+	headL1OriginID, err := rawdb.ReadHeadL1Origin(db)
+	if err != nil {
+		t.Fatalf("Failed to read head L1Origin: %v", err)
+	}
+	if head == 0 {
+		if headL1OriginID != nil {
+			t.Errorf("Head L1Origin mismatch: have %d, want nil", headL1OriginID)
+		}
+	} else if headL1OriginID == nil || headL1OriginID.Uint64() != head {
+		t.Errorf("Head L1Origin mismatch: have %v, want %d", headL1OriginID, head)
+	}
+}
+
+// Tests that the L1Origins are rolled back when the canonical chain is reorged to
+// a side chain, or rewound to one of its ancestors.
+func TestReorgRewindsL1Origins(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, canon, _ := GenerateChainWithGenesis(gspec, engine, 6, func(i int, gen *BlockGen) {
+		gen.SetCoinbase(common.Address{0x01})
+	})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(canon); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	writeTestL1Origins(db, canon, canon[len(canon)-1].NumberU64())
+	for _, block := range canon {
+		chain.AddSkippedTransactions(block, []*rawdb.SkippedTransaction{{Hash: block.Hash(), Reason: "test"}})
+	}
+
+	// Reorg to a side chain forking at block #3, the L1Origins of the dropped
+	// blocks should be deleted.
+	_, side, _ := GenerateChainWithGenesis(gspec, engine, 5, func(i int, gen *BlockGen) {
+		if i < 3 {
+			gen.SetCoinbase(common.Address{0x01})
+		} else {
+			gen.SetCoinbase(common.Address{0x02})
+		}
+	})
+	side = side[3:]
+	for _, block := range side {
+		if _, err := chain.InsertBlockWithoutSetHead(block, false); err != nil {
+			t.Fatalf("Failed to insert into chain: %v", err)
+		}
+	}
+	if _, err := chain.SetCanonical(side[len(side)-1]); err != nil {
+		t.Fatalf("Failed to set canonical head: %v", err)
+	}
+	verifyL1Origins(t, db, canon, 3)
+	verifySkippedTransactions(t, db, canon, 3)
+
+	// Write the L1Origins of the side chain, then rewind to block #2.
+	writeTestL1Origins(db, side, side[len(side)-1].NumberU64())
+	if _, err := chain.SetCanonical(canon[1]); err != nil {
+		t.Fatalf("Failed to set canonical head: %v", err)
+	}
+	verifyL1Origins(t, db, append(canon[:3:3], side...), 2)
+
+	// The transactions skipped in the rewound blocks are deleted too.
+	if err := chain.SetHead(1); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	verifySkippedTransactions(t, db, canon, 1)
+}
+
+// verifySkippedTransactions checks that only the skipped transactions of the given
+// blocks up to the given head are retained.
+func verifySkippedTransactions(t *testing.T, db ethdb.KeyValueReader, blocks types.Blocks, head uint64) {
+	t.Helper()
+
+	for _, block := range blocks {
+		txs, err := rawdb.ReadSkippedTransactions(db, block.Hash())
+		if err != nil {
+			t.Fatalf("Failed to read skipped transactions #%d: %v", block.NumberU64(), err)
+		}
+		if block.NumberU64() <= head && len(txs) != 1 {
+			t.Errorf("Skipped transactions #%d missing", block.NumberU64())
+		}
+		if block.NumberU64() > head && txs != nil {
+			t.Errorf("Skipped transactions #%d not deleted: %v", block.NumberU64(), txs)
+		}
+	}
+}
+
+// Tests that the head L1Origin is moved back over the L2 blocks without L1Origins,
+// e.g. the beacon synced ones, when the chain is rewound.
+func TestRewindSparseL1Origins(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 6, func(i int, gen *BlockGen) {})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	// Blocks #3-#5 have no L1Origin.
+	for _, block := range []*types.Block{blocks[0], blocks[1], blocks[5]} {
+		chain.WriteL1Origin(testL1Origin(block))
+	}
+	if err := chain.SetHead(4); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	verifyL1Origins(t, db, types.Blocks{blocks[0], blocks[1], blocks[5]}, 2)
+
+	// Rewinding to a block above the head L1Origin keeps it.
+	if err := chain.SetHead(3); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	verifyL1Origins(t, db, types.Blocks{blocks[0], blocks[1]}, 2)
+
+	if err := chain.SetHead(1); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	verifyL1Origins(t, db, types.Blocks{blocks[0], blocks[1]}, 1)
+
+	if err := chain.SetHead(0); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	verifyL1Origins(t, db, types.Blocks{blocks[0]}, 0)
+}
+
+func TestRewindToL1Height(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 6, func(i int, gen *BlockGen) {})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.RewindToL1Height(big.NewInt(1006)); err == nil {
+		t.Fatalf("Expected error when no L1Origin exists")
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	writeTestL1Origins(db, blocks, blocks[len(blocks)-1].NumberU64())
+
+	// No L2 block is proposed at or below the given L1 height.
+	if _, err := chain.RewindToL1Height(big.NewInt(1000)); err == nil {
+		t.Fatalf("Expected error when no L1Origin is at or below the given L1 height")
+	}
+	verifyL1Origins(t, db, blocks, 6)
+
+	// L1 height above the head L1Origin, nothing should be rewound.
+	l1Origin, err := chain.RewindToL1Height(big.NewInt(2000))
+	if err != nil {
+		t.Fatalf("Failed to rewind to L1 height: %v", err)
+	}
+	if l1Origin.BlockID.Uint64() != 6 || chain.CurrentBlock().Number.Uint64() != 6 {
+		t.Fatalf("Unexpected rewind, head L1Origin %d, head block %d", l1Origin.BlockID, chain.CurrentBlock().Number)
+	}
+
+	l1Origin, err = chain.RewindToL1Height(big.NewInt(1003))
+	if err != nil {
+		t.Fatalf("Failed to rewind to L1 height: %v", err)
+	}
+	if l1Origin.BlockID.Uint64() != 3 || l1Origin.L2BlockHash != blocks[2].Hash() {
+		t.Fatalf("Head L1Origin mismatch: have %d, want 3", l1Origin.BlockID)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[2].Hash() {
+		t.Fatalf("Head block mismatch: have %d, want 3", head.Number)
+	}
+	verifyL1Origins(t, db, blocks, 3)
+}
+
+// Tests that the L1Origins above the target are rolled back even if the chain head
+// is still below the target block.
+func TestRewindToL1HeightAboveHead(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 6, func(i int, gen *BlockGen) {})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Only blocks #1-#2 are inserted, but all the blocks are proposed on L1.
+	if n, err := chain.InsertChain(blocks[:2]); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	writeTestL1Origins(db, blocks, blocks[len(blocks)-1].NumberU64())
+
+	l1Origin, err := chain.RewindToL1Height(big.NewInt(1004))
+	if err != nil {
+		t.Fatalf("Failed to rewind to L1 height: %v", err)
+	}
+	if l1Origin.BlockID.Uint64() != 4 || l1Origin.L1BlockHeight.Uint64() > 1004 {
+		t.Fatalf("Head L1Origin mismatch: have %d, want 4", l1Origin.BlockID)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[1].Hash() {
+		t.Fatalf("Head block mismatch: have %d, want 2", head.Number)
+	}
+	verifyL1Origins(t, db, blocks, 4)
+}
+
+// Tests that preconfirmation blocks can be confirmed by attaching their L1Origins,
+// and the head preconfirmation L1Origin is rolled back when the chain is rewound.
+func TestPreconfL1Origins(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 6, func(i int, gen *BlockGen) {})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	// Blocks #1-#3 are proposed on L1, blocks #4-#6 are preconfirmation blocks.
+	for _, block := range blocks {
+		if block.NumberU64() <= 3 {
+			chain.WriteL1Origin(testL1Origin(block))
+		} else {
+			chain.WriteL1Origin(&rawdb.L1Origin{BlockID: block.Number(), L2BlockHash: block.Hash(), IsPreconfBlock: true})
+		}
+	}
+	checkHeads := func(confirmed, preconf uint64) {
+		t.Helper()
+
+		headL1OriginID, err := rawdb.ReadHeadL1Origin(db)
+		if err != nil || headL1OriginID == nil || headL1OriginID.Uint64() != confirmed {
+			t.Errorf("Head L1Origin mismatch: have %v, want %d, err %v", headL1OriginID, confirmed, err)
+		}
+		headPreconfID, err := rawdb.ReadHeadPreconfL1Origin(db)
+		if err != nil {
+			t.Fatalf("Failed to read head preconfirmation L1Origin: %v", err)
+		}
+		if preconf == 0 {
+			if headPreconfID != nil {
+				t.Errorf("Head preconfirmation L1Origin mismatch: have %d, want nil", headPreconfID)
+			}
+		} else if headPreconfID == nil || headPreconfID.Uint64() != preconf {
+			t.Errorf("Head preconfirmation L1Origin mismatch: have %v, want %d", headPreconfID, preconf)
+		}
+	}
+	checkHeads(3, 6)
+
+	// Confirm block #4, the L2 block hash must match the canonical one.
+	l1Origin := testL1Origin(blocks[3])
+	l1Origin.L2BlockHash = blocks[4].Hash()
+	if _, err := chain.ConfirmL1Origin(l1Origin); err == nil {
+		t.Fatalf("Expected error when confirming a non-canonical L2 block")
+	}
+	l1Origin.L2BlockHash = common.Hash{}
+	if _, err := chain.ConfirmL1Origin(l1Origin); err != nil {
+		t.Fatalf("Failed to confirm L1Origin: %v", err)
+	}
+	if l1Origin.L2BlockHash != blocks[3].Hash() {
+		t.Errorf("L2 block hash mismatch: have %x, want %x", l1Origin.L2BlockHash, blocks[3].Hash())
+	}
+	checkHeads(4, 6)
+
+	// The L1Origins of rewound preconfirmation blocks are deleted.
+	if err := chain.SetHead(5); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	checkHeads(4, 5)
+	if l1Origin, _ := rawdb.ReadL1Origin(db, big.NewInt(6)); l1Origin != nil {
+		t.Errorf("L1Origin #6 not rolled back: %v", l1Origin)
+	}
+	if err := chain.SetHead(4); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	checkHeads(4, 0)
+}
+
+// yieldingDatabase is a database which yields the processor on every read, so the
+// concurrent read-modify-writes interleave even on a single core.
+type yieldingDatabase struct {
+	ethdb.Database
+}
+
+func (db yieldingDatabase) Get(key []byte) ([]byte, error) {
+	runtime.Gosched()
+	return db.Database.Get(key)
+}
+
+// Tests that concurrent L1Origin writes of the L2 blocks proposed at the same L1
+// height don't overwrite each other's L1 block index entries.
+func TestConcurrentWriteL1Origin(t *testing.T) {
+	gspec := &Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	db := yieldingDatabase{rawdb.NewMemoryDatabase()}
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	var (
+		l1Height = big.NewInt(1000)
+		n        = 32
+		start    = make(chan struct{})
+		wg       sync.WaitGroup
+	)
+	for i := 1; i <= n; i++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			<-start
+			chain.WriteL1Origin(&rawdb.L1Origin{
+				BlockID:       big.NewInt(id),
+				L2BlockHash:   common.BigToHash(big.NewInt(id)),
+				L1BlockHeight: l1Height,
+				L1BlockHash:   common.Hash{0x01},
+			})
+		}(int64(i))
+	}
+	close(start)
+	wg.Wait()
+
+	l1Origins, err := rawdb.ReadL1OriginsByL1Height(db, l1Height)
+	if err != nil {
+		t.Fatalf("Failed to read indexed L1Origins: %v", err)
+	}
+	if len(l1Origins) != n {
+		t.Fatalf("Indexed L1Origins mismatch: have %d, want %d", len(l1Origins), n)
+	}
+}
+
+// l1OriginCounter is a database which counts the L1Origin keys read, and written
+// outside of the batches.
+type l1OriginCounter struct {
+	ethdb.Database
+	reads  int
+	writes int
+}
+
+func (db *l1OriginCounter) Get(key []byte) ([]byte, error) {
+	if bytes.HasPrefix(key, []byte("TKO:")) {
+		db.reads++
+	}
+	return db.Database.Get(key)
+}
+
+func (db *l1OriginCounter) Put(key []byte, value []byte) error {
+	if bytes.HasPrefix(key, []byte("TKO:")) {
+		db.writes++
+	}
+	return db.Database.Put(key, value)
+}
+
+func (db *l1OriginCounter) Delete(key []byte) error {
+	if bytes.HasPrefix(key, []byte("TKO:")) {
+		db.writes++
+	}
+	return db.Database.Delete(key)
+}
+
+// Tests that the L1Origins and the head L1Origin markers are rolled back together
+// in a batch, so the markers never point to deleted L1Origins.
+func TestRewindL1OriginsBatch(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 6, func(i int, gen *BlockGen) {})
+	db := &l1OriginCounter{Database: rawdb.NewMemoryDatabase()}
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	// Blocks #1-#4 are proposed on L1, blocks #5-#6 are preconfirmation blocks.
+	for _, block := range blocks {
+		if block.NumberU64() <= 4 {
+			chain.WriteL1Origin(testL1Origin(block))
+		} else {
+			chain.WriteL1Origin(&rawdb.L1Origin{BlockID: block.Number(), L2BlockHash: block.Hash(), IsPreconfBlock: true})
+		}
+	}
+	db.writes = 0
+	if err := chain.SetHead(2); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	if db.writes != 0 {
+		t.Fatalf("L1Origin keys written outside of a batch: %d", db.writes)
+	}
+	verifyL1Origins(t, db, blocks, 2)
+	if headPreconfID, _ := rawdb.ReadHeadPreconfL1Origin(db); headPreconfID != nil {
+		t.Fatalf("Head preconfirmation L1Origin not rolled back: %d", headPreconfID)
+	}
+}
+
+// Tests that the head L1Origin is found by walking the L2 blocks, not the L1 block
+// heights, which are far apart from each other.
+func TestRewindDistantL1Heights(t *testing.T) {
+	var (
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 6, func(i int, gen *BlockGen) {})
+	db := &l1OriginCounter{Database: rawdb.NewMemoryDatabase()}
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	// Each L2 block is proposed 1M L1 blocks after its parent.
+	for _, block := range blocks {
+		l1Origin := testL1Origin(block)
+		l1Origin.L1BlockHeight = new(big.Int).Mul(block.Number(), big.NewInt(1_000_000))
+		chain.WriteL1Origin(l1Origin)
+	}
+	db.reads = 0
+	if err := chain.SetHead(4); err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+	l1Origin, err := chain.RewindToL1Height(big.NewInt(2_500_000))
+	if err != nil {
+		t.Fatalf("Failed to rewind to L1 height: %v", err)
+	}
+	if l1Origin.BlockID.Uint64() != 2 || chain.CurrentBlock().Number.Uint64() != 2 {
+		t.Fatalf("Unexpected rewind, head L1Origin %d, head block %d", l1Origin.BlockID, chain.CurrentBlock().Number)
+	}
+	if db.reads > 100 {
+		t.Fatalf("Too many L1Origin reads: %d", db.reads)
+	}
+}
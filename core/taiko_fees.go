@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// SplitTaikoBaseFee splits the base fee paid for the given gas between the treasury
+// and the coinbase, which receives the given percentage of it.
+func SplitTaikoBaseFee(baseFee *big.Int, gasUsed uint64, sharingPctg uint8) (treasury *big.Int, coinbase *big.Int) {
+	total := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed))
+	coinbase = new(big.Int).Mul(total, new(big.Int).SetUint64(uint64(sharingPctg)))
+	coinbase.Div(coinbase, big.NewInt(100))
+	return total.Sub(total, coinbase), coinbase
+}
+
+// TaikoFeeBreakdown computes the fee revenue of the given L2 block from its receipts,
+// the same way the state transition pays the fees of each transaction. The first
+// transaction of the block is the anchor transaction, which pays no fees.
+func TaikoFeeBreakdown(config *params.ChainConfig, block *types.Block, receipts types.Receipts) (*types.BlockFeeBreakdown, error) {
+	if len(receipts) != len(block.Transactions()) {
+		return nil, fmt.Errorf("receipts mismatch: have %d, want %d", len(receipts), len(block.Transactions()))
+	}
+	var (
+		fees        = types.NewFeeBreakdown()
+		baseFee     = block.BaseFee()
+		sharingPctg = config.TaikoParamsAt(block.Number(), block.Time()).BasefeeSharingPctg(block.Extra())
+	)
+	for i, receipt := range receipts {
+		if i == 0 && config.Taiko {
+			fees.AnchorTxs++
+			fees.AnchorGasUsed += hexutil.Uint64(receipt.GasUsed)
+			continue
+		}
+		price := receipt.EffectiveGasPrice
+		if baseFee == nil {
+			fees.PriorityFees.ToInt().Add(fees.PriorityFees.ToInt(), new(big.Int).Mul(price, new(big.Int).SetUint64(receipt.GasUsed)))
+			continue
+		}
+		tip := new(big.Int).Sub(price, baseFee)
+		fees.PriorityFees.ToInt().Add(fees.PriorityFees.ToInt(), tip.Mul(tip, new(big.Int).SetUint64(receipt.GasUsed)))
+
+		if config.Taiko {
+			treasury, coinbase := SplitTaikoBaseFee(baseFee, receipt.GasUsed, sharingPctg)
+			fees.TreasuryIncome.ToInt().Add(fees.TreasuryIncome.ToInt(), treasury)
+			fees.CoinbaseBaseFeeShare.ToInt().Add(fees.CoinbaseBaseFeeShare.ToInt(), coinbase)
+		}
+	}
+	return &types.BlockFeeBreakdown{
+		Number:       hexutil.Uint64(block.NumberU64()),
+		Hash:         block.Hash(),
+		FeeBreakdown: fees,
+	}, nil
+}
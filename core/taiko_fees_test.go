@@ -0,0 +1,146 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestSplitTaikoBaseFee(t *testing.T) {
+	treasury, coinbase := SplitTaikoBaseFee(big.NewInt(7), 21000, 75)
+	if treasury.Cmp(big.NewInt(36750)) != 0 || coinbase.Cmp(big.NewInt(110250)) != 0 {
+		t.Fatalf("unexpected split: treasury %v, coinbase %v", treasury, coinbase)
+	}
+	// The coinbase share is rounded down.
+	treasury, coinbase = SplitTaikoBaseFee(big.NewInt(1), 3, 50)
+	if treasury.Cmp(big.NewInt(2)) != 0 || coinbase.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("unexpected split: treasury %v, coinbase %v", treasury, coinbase)
+	}
+}
+
+// Tests that the fee breakdown computed from the receipts matches the balance changes
+// of the fee payments while importing the blocks.
+func TestTaikoFeeBreakdown(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Taiko = true
+	config.OntakeBlock = big.NewInt(2)
+
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		signer   = types.LatestSigner(&config)
+		engine   = taiko.New(&config)
+		taikoL2  = config.TaikoL2ContractAddress()
+		coinbase = common.Address{0xc0}
+		genesis  = &Genesis{
+			Config:  &config,
+			Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		nonce uint64
+	)
+	_, blocks, _ := GenerateChainWithGenesis(genesis, engine, 3, func(i int, g *BlockGen) {
+		g.SetCoinbase(coinbase)
+		g.SetExtra([]byte{75})
+
+		var (
+			anchor *types.Transaction
+			err    error
+		)
+		if config.IsOntake(g.Number(), g.Timestamp()) {
+			anchor, err = engine.NewAnchorV2Tx(g.header, uint64(i), 1, common.Hash{}, 0, &taiko.BaseFeeConfig{})
+		} else {
+			anchor = types.NewTx(&types.DynamicFeeTx{
+				ChainID:   config.ChainID,
+				Nonce:     uint64(i),
+				GasTipCap: common.Big0,
+				GasFeeCap: g.BaseFee(),
+				Gas:       taiko.AnchorGasLimit,
+				To:        &taikoL2,
+				Data:      append(append([]byte{}, taiko.AnchorSelector...), make([]byte, 4*common.HashLength)...),
+			})
+			var sig []byte
+			if sig, err = taiko.SignAnchor(signer.Hash(anchor).Bytes()); err == nil {
+				anchor, err = anchor.WithSignature(signer, sig)
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := anchor.MarkAsAnchor(); err != nil {
+			t.Fatal(err)
+		}
+		g.AddTx(anchor)
+
+		for j := int64(0); j < 2; j++ {
+			g.AddTx(types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   config.ChainID,
+				Nonce:     nonce,
+				GasTipCap: big.NewInt(params.GWei * (j + 1)),
+				GasFeeCap: new(big.Int).Add(g.BaseFee(), big.NewInt(params.GWei*3/2)), // Caps the tip of the second transaction
+				Gas:       params.TxGas,
+				To:        &common.Address{0x01},
+			}))
+			nonce++
+		}
+	})
+
+	// Import the blocks, collecting the fee payments.
+	want := types.NewFeeBreakdown()
+	hooks := &tracing.Hooks{
+		OnBalanceChange: func(addr common.Address, prev, new *big.Int, reason tracing.BalanceChangeReason) {
+			diff := new.Sub(new, prev)
+			switch reason {
+			case tracing.BalanceIncreaseTreasury:
+				if addr != config.TaikoTreasuryAddress() {
+					t.Errorf("treasury income paid to %v", addr)
+				}
+				want.TreasuryIncome.ToInt().Add(want.TreasuryIncome.ToInt(), diff)
+			case tracing.BalanceIncreaseBaseFeeSharing:
+				want.CoinbaseBaseFeeShare.ToInt().Add(want.CoinbaseBaseFeeShare.ToInt(), diff)
+			case tracing.BalanceIncreaseRewardTransactionFee:
+				want.PriorityFees.ToInt().Add(want.PriorityFees.ToInt(), diff)
+			}
+		},
+	}
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, engine, vm.Config{Tracer: hooks}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	have := types.NewFeeBreakdown()
+	for _, block := range blocks {
+		fees, err := TaikoFeeBreakdown(&config, block, chain.GetReceiptsByHash(block.Hash()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fees.AnchorTxs != 1 || fees.AnchorGasUsed == 0 {
+			t.Fatalf("block %d: expected the anchor transaction to be excluded, got %d anchors using %d gas", block.NumberU64(), fees.AnchorTxs, fees.AnchorGasUsed)
+		}
+		// The base fee is shared since Ontake only.
+		if shared := fees.CoinbaseBaseFeeShare.ToInt().Sign() != 0; shared != config.IsOntake(block.Number(), block.Time()) {
+			t.Fatalf("block %d: unexpected base fee sharing %v", block.NumberU64(), fees.CoinbaseBaseFeeShare)
+		}
+		have.Add(fees.FeeBreakdown)
+	}
+	have.AnchorTxs, have.AnchorGasUsed = 0, 0
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("fee breakdown mismatch: have %+v, want %+v", have, want)
+	}
+	if want.TreasuryIncome.ToInt().Sign() == 0 || want.PriorityFees.ToInt().Sign() == 0 {
+		t.Fatalf("expected fees to be paid, got %+v", want)
+	}
+}
@@ -5,63 +5,38 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	taikoGenesis "github.com/ethereum/go-ethereum/core/taiko_genesis"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
 
-var (
-	InternalDevnetOntakeBlock = new(big.Int).SetUint64(2)
-	HeklaOntakeBlock          = new(big.Int).SetUint64(840_512)
-	MainnetOntakeBlock        = new(big.Int).SetUint64(538_304)
-)
+// taikoGenesisAllocs is the registry of the genesis allocations of the known Taiko
+// networks, the chain configs are registered in the params package.
+var taikoGenesisAllocs = map[uint64][]byte{
+	params.TaikoMainnetNetworkID.Uint64():     taikoGenesis.MainnetGenesisAllocJSON,
+	params.TaikoInternalL2ANetworkID.Uint64(): taikoGenesis.InternalL2AGenesisAllocJSON,
+	params.TaikoInternalL2BNetworkID.Uint64(): taikoGenesis.InternalL2BGenesisAllocJSON,
+	params.SnaefellsjokullNetworkID.Uint64():  taikoGenesis.SnaefellsjokullGenesisAllocJSON,
+	params.AskjaNetworkID.Uint64():            taikoGenesis.AskjaGenesisAllocJSON,
+	params.GrimsvotnNetworkID.Uint64():        taikoGenesis.GrimsvotnGenesisAllocJSON,
+	params.EldfellNetworkID.Uint64():          taikoGenesis.EldfellGenesisAllocJSON,
+	params.JolnirNetworkID.Uint64():           taikoGenesis.JolnirGenesisAllocJSON,
+	params.KatlaNetworkID.Uint64():            taikoGenesis.KatlaGenesisAllocJSON,
+	params.HeklaNetworkID.Uint64():            taikoGenesis.HeklaGenesisAllocJSON,
+}
 
-// TaikoGenesisBlock returns the Taiko network genesis block configs.
+// TaikoGenesisBlock returns the Taiko network genesis block configs, unknown networks
+// fall back to the internal devnet. Each call returns a new genesis with its own
+// chain config.
 func TaikoGenesisBlock(networkID uint64) *Genesis {
-	chainConfig := params.TaikoChainConfig
-
-	var allocJSON []byte
-	switch networkID {
-	case params.TaikoMainnetNetworkID.Uint64():
-		chainConfig.ChainID = params.TaikoMainnetNetworkID
-		chainConfig.OntakeBlock = MainnetOntakeBlock
-		allocJSON = taikoGenesis.MainnetGenesisAllocJSON
-	case params.TaikoInternalL2ANetworkID.Uint64():
-		chainConfig.ChainID = params.TaikoInternalL2ANetworkID
-		chainConfig.OntakeBlock = InternalDevnetOntakeBlock
-		allocJSON = taikoGenesis.InternalL2AGenesisAllocJSON
-	case params.TaikoInternalL2BNetworkID.Uint64():
-		chainConfig.ChainID = params.TaikoInternalL2BNetworkID
-		allocJSON = taikoGenesis.InternalL2BGenesisAllocJSON
-	case params.SnaefellsjokullNetworkID.Uint64():
-		chainConfig.ChainID = params.SnaefellsjokullNetworkID
-		allocJSON = taikoGenesis.SnaefellsjokullGenesisAllocJSON
-	case params.AskjaNetworkID.Uint64():
-		chainConfig.ChainID = params.AskjaNetworkID
-		allocJSON = taikoGenesis.AskjaGenesisAllocJSON
-	case params.GrimsvotnNetworkID.Uint64():
-		chainConfig.ChainID = params.GrimsvotnNetworkID
-		allocJSON = taikoGenesis.GrimsvotnGenesisAllocJSON
-	case params.EldfellNetworkID.Uint64():
-		chainConfig.ChainID = params.EldfellNetworkID
-		allocJSON = taikoGenesis.EldfellGenesisAllocJSON
-	case params.JolnirNetworkID.Uint64():
-		chainConfig.ChainID = params.JolnirNetworkID
-		allocJSON = taikoGenesis.JolnirGenesisAllocJSON
-	case params.KatlaNetworkID.Uint64():
-		chainConfig.ChainID = params.KatlaNetworkID
-		allocJSON = taikoGenesis.KatlaGenesisAllocJSON
-	case params.HeklaNetworkID.Uint64():
-		chainConfig.ChainID = params.HeklaNetworkID
-		chainConfig.OntakeBlock = HeklaOntakeBlock
-		allocJSON = taikoGenesis.HeklaGenesisAllocJSON
-	default:
-		chainConfig.ChainID = params.TaikoInternalL2ANetworkID
-		chainConfig.OntakeBlock = InternalDevnetOntakeBlock
-		allocJSON = taikoGenesis.InternalL2AGenesisAllocJSON
+	chainConfig, ok := params.TaikoChainConfigByNetworkID(networkID)
+	if !ok {
+		networkID = params.TaikoInternalL2ANetworkID.Uint64()
+		chainConfig, _ = params.TaikoChainConfigByNetworkID(networkID)
 	}
 
 	var alloc GenesisAlloc
-	if err := alloc.UnmarshalJSON(allocJSON); err != nil {
+	if err := alloc.UnmarshalJSON(taikoGenesisAllocs[networkID]); err != nil {
 		log.Crit("unmarshal alloc json error", "error", err)
 	}
 
@@ -75,3 +50,17 @@ func TaikoGenesisBlock(networkID uint64) *Genesis {
 		BaseFee:    new(big.Int).SetUint64(10_000_000),
 	}
 }
+
+// TaikoDeveloperGenesisBlock returns the genesis of the Taiko developer mode, which
+// is the developer genesis on the Taiko developer chain config, with the given
+// predeployed protocol contracts, e.g. TaikoL2 and SignalService, generated by the
+// protocol for the chain ID of the developer chain.
+func TaikoDeveloperGenesisBlock(gasLimit uint64, faucet *common.Address, predeploys types.GenesisAlloc) *Genesis {
+	genesis := DeveloperGenesisBlock(gasLimit, faucet)
+	genesis.Config = params.TaikoDevChainConfig()
+	for addr, account := range predeploys {
+		genesis.Alloc[addr] = account
+	}
+
+	return genesis
+}
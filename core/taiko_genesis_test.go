@@ -0,0 +1,50 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestTaikoGenesisBlock(t *testing.T) {
+	var (
+		hekla   = TaikoGenesisBlock(params.HeklaNetworkID.Uint64())
+		katla   = TaikoGenesisBlock(params.KatlaNetworkID.Uint64())
+		unknown = TaikoGenesisBlock(1)
+	)
+	// The genesis of each network has its own chain config.
+	if hekla.Config.ChainID.Cmp(params.HeklaNetworkID) != 0 || hekla.Config.OntakeBlock == nil {
+		t.Fatalf("hekla config mismatch: chain ID %v, Ontake block %v", hekla.Config.ChainID, hekla.Config.OntakeBlock)
+	}
+	if katla.Config.ChainID.Cmp(params.KatlaNetworkID) != 0 || katla.Config.OntakeBlock != nil {
+		t.Fatalf("katla config mismatch: chain ID %v, Ontake block %v", katla.Config.ChainID, katla.Config.OntakeBlock)
+	}
+	if unknown.Config.ChainID.Cmp(params.TaikoInternalL2ANetworkID) != 0 {
+		t.Fatalf("unknown network config mismatch: chain ID %v", unknown.Config.ChainID)
+	}
+	if len(hekla.Alloc) == 0 || len(katla.Alloc) == 0 {
+		t.Fatal("empty genesis alloc")
+	}
+}
+
+func TestTaikoDeveloperGenesisBlock(t *testing.T) {
+	var (
+		faucet  = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+		taikoL2 = params.TaikoDevChainConfig().TaikoL2ContractAddress()
+	)
+	genesis := TaikoDeveloperGenesisBlock(11_500_000, &faucet, types.GenesisAlloc{
+		taikoL2: {Code: []byte{0x00}, Balance: common.Big0},
+	})
+	if !genesis.Config.Taiko || !genesis.Config.IsOntake(common.Big0, 0) {
+		t.Fatal("expected the Taiko developer chain config")
+	}
+	if genesis.Alloc[faucet].Balance == nil || !bytes.Equal(genesis.Alloc[taikoL2].Code, []byte{0x00}) {
+		t.Fatalf("expected the faucet and the predeploys in the alloc: %v", genesis.Alloc)
+	}
+	if _, ok := TaikoDeveloperGenesisBlock(11_500_000, &faucet, nil).Alloc[taikoL2]; ok {
+		t.Fatal("expected no TaikoL2 without predeploys")
+	}
+}
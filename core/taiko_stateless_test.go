@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the blocks of a Taiko chain are cross validated by the stateless
+// execution, which credits the withdrawals in wei like the Taiko engine.
+func TestTaikoStatelessSelfValidation(t *testing.T) {
+	var (
+		config = params.TaikoDevChainConfig()
+		gspec  = &Genesis{Config: config, GasLimit: params.GenesisGasLimit}
+		engine = taiko.New(config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 2, func(i int, gen *BlockGen) {
+		anchor, err := engine.NewAnchorV2Tx(gen.header, uint64(i), uint64(i), common.Hash{}, 0, &taiko.BaseFeeConfig{})
+		if err != nil {
+			t.Fatalf("Failed to create anchor transaction: %v", err)
+		}
+		if err := anchor.MarkAsAnchor(); err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(anchor)
+		gen.AddWithdrawal(&types.Withdrawal{Address: common.Address{0x01}, Amount: 1000})
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{StatelessSelfValidation: true}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Block %d: failed to insert into chain: %v", n, err)
+	}
+	state, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := state.GetBalance(common.Address{0x01}); balance.Uint64() != 2000 {
+		t.Fatalf("Unexpected withdrawal balance: have %v, want 2000", balance)
+	}
+}
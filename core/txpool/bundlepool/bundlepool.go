@@ -0,0 +1,282 @@
+// Package bundlepool implements a pool of transaction bundles sent by searchers,
+// which are included into the L2 transactions lists all together or not at all.
+package bundlepool
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// maxBundles is the maximum number of bundles kept in the pool.
+	maxBundles = 1024
+
+	// maxBundleTxs is the maximum number of transactions in a bundle.
+	maxBundleTxs = 64
+)
+
+var (
+	// ErrEmptyBundle is returned if a bundle without transactions is sent.
+	ErrEmptyBundle = errors.New("empty bundle")
+
+	// ErrBundleTooLarge is returned if a bundle has more transactions than allowed.
+	ErrBundleTooLarge = errors.New("bundle too large")
+
+	// ErrBlobTxInBundle is returned if a bundle contains a blob transaction, which
+	// can't be included in the L2 transactions lists.
+	ErrBlobTxInBundle = errors.New("blob transaction in bundle")
+
+	// ErrInvalidBlockRange is returned if the target block range of a bundle is
+	// empty.
+	ErrInvalidBlockRange = errors.New("invalid bundle block range")
+
+	// ErrBundleExpired is returned if the target block range of a bundle is already
+	// behind the chain head.
+	ErrBundleExpired = errors.New("bundle expired")
+
+	// ErrUnknownRevertingTx is returned if a transaction allowed to revert is not
+	// part of the bundle.
+	ErrUnknownRevertingTx = errors.New("reverting transaction not in bundle")
+
+	// ErrAlreadyKnown is returned if the bundle is already contained in the pool.
+	ErrAlreadyKnown = errors.New("already known")
+
+	// ErrBundlePoolFull is returned if the pool can't accept more bundles.
+	ErrBundlePoolFull = errors.New("bundle pool is full")
+)
+
+// BlockChain defines the minimal set of methods needed to back a bundle pool with
+// a chain.
+type BlockChain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// CurrentBlock returns the current head of the chain.
+	CurrentBlock() *types.Header
+
+	// StateAt returns a state database for a given root hash (generally the head).
+	StateAt(root common.Hash) (*state.StateDB, error)
+
+	// SubscribeChainHeadEvent subscribes to new blocks being added to the chain.
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Bundle is an ordered group of transactions, which are included into a L2 block
+// all together or not at all.
+type Bundle struct {
+	Txs types.Transactions
+
+	// Range of the L2 block numbers the bundle targets, zero means unbounded.
+	MinBlock uint64
+	MaxBlock uint64
+
+	// Transactions of the bundle which are allowed to revert, any other reverted
+	// transaction drops the whole bundle.
+	RevertingTxHashes []common.Hash
+
+	hash common.Hash
+}
+
+// Hash returns the hash of the bundle, which is the hash of the concatenated hashes
+// of its transactions.
+func (b *Bundle) Hash() common.Hash {
+	if b.hash == (common.Hash{}) {
+		hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+		for _, tx := range b.Txs {
+			hashes = append(hashes, tx.Hash().Bytes()...)
+		}
+		b.hash = crypto.Keccak256Hash(hashes)
+	}
+	return b.hash
+}
+
+// CanRevert returns whether the given transaction of the bundle is allowed to revert.
+func (b *Bundle) CanRevert(hash common.Hash) bool {
+	return slices.Contains(b.RevertingTxHashes, hash)
+}
+
+// Targets returns whether the bundle can be included in the given L2 block.
+func (b *Bundle) Targets(number uint64) bool {
+	return number >= b.MinBlock && (b.MaxBlock == 0 || number <= b.MaxBlock)
+}
+
+// expired returns whether the bundle can't be included after the given head anymore.
+func (b *Bundle) expired(head uint64) bool {
+	return b.MaxBlock != 0 && b.MaxBlock <= head
+}
+
+// BundlePool keeps the bundles sent by searchers until they expire, or can't be
+// included anymore since their transactions are already stale.
+type BundlePool struct {
+	chain  BlockChain
+	signer types.Signer
+
+	bundles map[common.Hash]*Bundle
+	order   []common.Hash // Hashes of the bundles in arrival order
+	mu      sync.RWMutex
+
+	headSub event.Subscription
+	wg      sync.WaitGroup
+}
+
+// New creates a new bundle pool, which drops the outdated bundles whenever the
+// head of the given chain changes.
+func New(chain BlockChain) *BundlePool {
+	pool := &BundlePool{
+		chain:   chain,
+		signer:  types.LatestSigner(chain.Config()),
+		bundles: make(map[common.Hash]*Bundle),
+	}
+	heads := make(chan core.ChainHeadEvent, 10)
+	pool.headSub = chain.SubscribeChainHeadEvent(heads)
+
+	pool.wg.Add(1)
+	go pool.loop(heads)
+
+	return pool
+}
+
+// loop is the pool's main event loop, waiting for and reacting to chain head
+// events.
+func (p *BundlePool) loop(heads chan core.ChainHeadEvent) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case ev := <-heads:
+			p.reset(ev.Block.Header())
+
+		case <-p.headSub.Err():
+			return
+		}
+	}
+}
+
+// Close terminates the bundle pool.
+func (p *BundlePool) Close() {
+	p.headSub.Unsubscribe()
+	p.wg.Wait()
+}
+
+// Add validates the given bundle and inserts it into the pool, it returns the hash
+// of the bundle.
+func (p *BundlePool) Add(bundle *Bundle) (common.Hash, error) {
+	if err := p.validate(bundle); err != nil {
+		return common.Hash{}, err
+	}
+	hash := bundle.Hash()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.bundles[hash]; ok {
+		return common.Hash{}, ErrAlreadyKnown
+	}
+	if len(p.bundles) >= maxBundles {
+		return common.Hash{}, ErrBundlePoolFull
+	}
+	p.bundles[hash] = bundle
+	p.order = append(p.order, hash)
+
+	log.Debug("Added bundle to pool", "hash", hash, "txs", len(bundle.Txs), "minBlock", bundle.MinBlock, "maxBlock", bundle.MaxBlock)
+	return hash, nil
+}
+
+// validate checks whether a bundle can be included in the following L2 blocks.
+func (p *BundlePool) validate(bundle *Bundle) error {
+	if len(bundle.Txs) == 0 {
+		return ErrEmptyBundle
+	}
+	if len(bundle.Txs) > maxBundleTxs {
+		return fmt.Errorf("%w: %d > %d", ErrBundleTooLarge, len(bundle.Txs), maxBundleTxs)
+	}
+	if bundle.MaxBlock != 0 && bundle.MaxBlock < bundle.MinBlock {
+		return fmt.Errorf("%w: [%d, %d]", ErrInvalidBlockRange, bundle.MinBlock, bundle.MaxBlock)
+	}
+	if head := p.chain.CurrentBlock().Number.Uint64(); bundle.expired(head) {
+		return fmt.Errorf("%w: max block %d, head %d", ErrBundleExpired, bundle.MaxBlock, head)
+	}
+	hashes := make(map[common.Hash]struct{}, len(bundle.Txs))
+	for _, tx := range bundle.Txs {
+		if tx.Type() == types.BlobTxType {
+			return ErrBlobTxInBundle
+		}
+		if _, err := types.Sender(p.signer, tx); err != nil {
+			return fmt.Errorf("invalid transaction %s: %w", tx.Hash(), err)
+		}
+		hashes[tx.Hash()] = struct{}{}
+	}
+	for _, hash := range bundle.RevertingTxHashes {
+		if _, ok := hashes[hash]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownRevertingTx, hash)
+		}
+	}
+	return nil
+}
+
+// Get returns the bundle with the given hash, if it's contained in the pool.
+func (p *BundlePool) Get(hash common.Hash) *Bundle {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.bundles[hash]
+}
+
+// Pending returns the bundles targeting the given L2 block, in arrival order.
+func (p *BundlePool) Pending(number uint64) []*Bundle {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var pending []*Bundle
+	for _, hash := range p.order {
+		if bundle := p.bundles[hash]; bundle.Targets(number) {
+			pending = append(pending, bundle)
+		}
+	}
+	return pending
+}
+
+// reset drops the bundles which can't be included on top of the given head, since
+// they either expired, or some of their transactions are already stale.
+func (p *BundlePool) reset(head *types.Header) {
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		log.Error("Failed to reset bundle pool state", "err", err)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.order = slices.DeleteFunc(p.order, func(hash common.Hash) bool {
+		bundle := p.bundles[hash]
+		if bundle.expired(head.Number.Uint64()) || p.stale(statedb, bundle) {
+			delete(p.bundles, hash)
+			log.Debug("Dropped outdated bundle", "hash", hash, "head", head.Number)
+			return true
+		}
+		return false
+	})
+}
+
+// stale returns whether the nonce of any transaction of the bundle is already used.
+func (p *BundlePool) stale(statedb *state.StateDB, bundle *Bundle) bool {
+	for _, tx := range bundle.Txs {
+		from, _ := types.Sender(p.signer, tx) // Already validated
+		if tx.Nonce() < statedb.GetNonce(from) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package bundlepool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// testBlockChain is a mock of the live chain for testing the pool.
+type testBlockChain struct {
+	head          *types.Header
+	statedb       *state.StateDB
+	chainHeadFeed event.Feed
+}
+
+func (bc *testBlockChain) Config() *params.ChainConfig                 { return params.TestChainConfig }
+func (bc *testBlockChain) CurrentBlock() *types.Header                 { return bc.head }
+func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) { return bc.statedb, nil }
+
+func (bc *testBlockChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return bc.chainHeadFeed.Subscribe(ch)
+}
+
+// setHead moves the head of the chain to the given number, and notifies the pool.
+func (bc *testBlockChain) setHead(number uint64) {
+	bc.head = &types.Header{Number: new(big.Int).SetUint64(number)}
+	bc.chainHeadFeed.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(bc.head)})
+}
+
+func newTestBlockChain() *testBlockChain {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil), nil))
+	return &testBlockChain{head: &types.Header{Number: new(big.Int)}, statedb: statedb}
+}
+
+func newTestTx(key *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+	return types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.DynamicFeeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		To:        &common.Address{},
+		Gas:       params.TxGas,
+		GasFeeCap: big.NewInt(params.GWei),
+		GasTipCap: big.NewInt(params.GWei),
+	})
+}
+
+// waitForBundles waits until the pool contains the given number of bundles.
+func waitForBundles(t *testing.T, pool *BundlePool, number uint64, count int) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if len(pool.Pending(number)) == count {
+			return
+		}
+	}
+	t.Fatalf("pending bundles mismatch: have %d, want %d", len(pool.Pending(number)), count)
+}
+
+func TestBundleValidation(t *testing.T) {
+	var (
+		chain   = newTestBlockChain()
+		pool    = New(chain)
+		key, _  = crypto.GenerateKey()
+		tx      = newTestTx(key, 0)
+		blobTx  = types.NewTx(&types.BlobTx{})
+		tooMany = make(types.Transactions, maxBundleTxs+1)
+	)
+	defer pool.Close()
+
+	for i := range tooMany {
+		tooMany[i] = newTestTx(key, uint64(i))
+	}
+	chain.head = &types.Header{Number: big.NewInt(10)}
+
+	tests := []struct {
+		bundle *Bundle
+		err    error
+	}{
+		{&Bundle{}, ErrEmptyBundle},
+		{&Bundle{Txs: tooMany}, ErrBundleTooLarge},
+		{&Bundle{Txs: types.Transactions{tx}, MinBlock: 20, MaxBlock: 15}, ErrInvalidBlockRange},
+		{&Bundle{Txs: types.Transactions{tx}, MaxBlock: 10}, ErrBundleExpired},
+		{&Bundle{Txs: types.Transactions{blobTx}}, ErrBlobTxInBundle},
+		{&Bundle{Txs: types.Transactions{tx}, RevertingTxHashes: []common.Hash{{0x01}}}, ErrUnknownRevertingTx},
+		{&Bundle{Txs: types.Transactions{tx}, MaxBlock: 11, RevertingTxHashes: []common.Hash{tx.Hash()}}, nil},
+		{&Bundle{Txs: types.Transactions{tx}, MaxBlock: 11}, ErrAlreadyKnown},
+	}
+	for i, tt := range tests {
+		if _, err := pool.Add(tt.bundle); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+func TestBundlePending(t *testing.T) {
+	var (
+		chain  = newTestBlockChain()
+		pool   = New(chain)
+		key, _ = crypto.GenerateKey()
+	)
+	defer pool.Close()
+
+	var (
+		first  = &Bundle{Txs: types.Transactions{newTestTx(key, 0), newTestTx(key, 1)}, MinBlock: 2}
+		second = &Bundle{Txs: types.Transactions{newTestTx(key, 2)}, MaxBlock: 3}
+		third  = &Bundle{Txs: types.Transactions{newTestTx(key, 3)}}
+	)
+	for _, bundle := range []*Bundle{first, second, third} {
+		hash, err := pool.Add(bundle)
+		if err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+		if pool.Get(hash) != bundle {
+			t.Fatalf("bundle %s not found", hash)
+		}
+	}
+	for number, want := range map[uint64][]*Bundle{
+		1: {second, third},
+		2: {first, second, third},
+		4: {first, third},
+	} {
+		pending := pool.Pending(number)
+		if len(pending) != len(want) {
+			t.Fatalf("block %d: pending bundles mismatch: have %d, want %d", number, len(pending), len(want))
+		}
+		for i := range want {
+			if pending[i] != want[i] {
+				t.Errorf("block %d: pending bundle %d mismatch: have %s, want %s", number, i, pending[i].Hash(), want[i].Hash())
+			}
+		}
+	}
+
+	// Expired bundles are dropped on new heads.
+	chain.setHead(3)
+	waitForBundles(t, pool, 2, 2)
+
+	// Bundles with stale transactions are dropped on new heads.
+	chain.statedb.SetNonce(crypto.PubkeyToAddress(key.PublicKey), 1)
+	chain.setHead(4)
+	waitForBundles(t, pool, 4, 1)
+	if pending := pool.Pending(4); pending[0] != third {
+		t.Fatalf("pending bundle mismatch: have %s, want %s", pending[0].Hash(), third.Hash())
+	}
+}
@@ -136,6 +136,9 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	// CHANGE(taiko): fee cap checks on Taiko chains.
+	Taiko txpool.TaikoConfig
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -613,6 +616,7 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 			1<<types.DynamicFeeTxType,
 		MaxSize: txMaxSize,
 		MinTip:  pool.gasTip.Load().ToBig(),
+		Taiko:   &pool.config.Taiko, // CHANGE(taiko): apply the configured fee cap checks.
 	}
 	if local {
 		opts.MinTip = new(big.Int)
@@ -0,0 +1,63 @@
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TaikoConfig are the fee cap checks of the transaction pools on Taiko chains.
+type TaikoConfig struct {
+	// MinBaseFees overrides the minimum base fee of the Taiko forks, keyed by the
+	// lower case fork name, zero disables the check of the fork.
+	MinBaseFees map[string]uint64 `toml:",omitempty"`
+
+	// AllowZeroFeeCap accepts transactions with a zero fee cap.
+	AllowZeroFeeCap bool `toml:",omitempty"`
+}
+
+// Validate checks that the minimum base fees are keyed by the lower case names of
+// known Taiko forks.
+func (c *TaikoConfig) Validate() error {
+	for name := range c.MinBaseFees {
+		if fork, ok := params.TaikoForkByName(name); !ok || name != strings.ToLower(fork.String()) {
+			return fmt.Errorf("unknown Taiko fork %q", name)
+		}
+	}
+	return nil
+}
+
+// minBaseFee returns the minimum fee cap of the Taiko fork activated at the given
+// head, or nil if there is none.
+func (c *TaikoConfig) minBaseFee(config *params.ChainConfig, head *types.Header) *big.Int {
+	fork := config.TaikoForkAt(head.Number, head.Time)
+	if c != nil {
+		if fee, ok := c.MinBaseFees[strings.ToLower(fork.String())]; ok {
+			if fee == 0 {
+				return nil
+			}
+			return new(big.Int).SetUint64(fee)
+		}
+	}
+	return fork.Params().MinBaseFee
+}
+
+// validateTaikoFeeCap checks the fee cap of the given transaction against the
+// minimum base fee of the active Taiko fork, and rejects zero fee caps unless
+// allowed by the config.
+func validateTaikoFeeCap(tx *types.Transaction, head *types.Header, opts *ValidationOptions) error {
+	if !opts.Config.Taiko {
+		return nil
+	}
+	if minBaseFee := opts.Taiko.minBaseFee(opts.Config, head); minBaseFee != nil && tx.GasFeeCap().Cmp(minBaseFee) < 0 {
+		return fmt.Errorf("max fee per gas is less than the minimum base fee (%v wei)", minBaseFee)
+	}
+	if tx.GasFeeCap().Sign() == 0 && (opts.Taiko == nil || !opts.Taiko.AllowZeroFeeCap) {
+		return errors.New("max fee per gas is zero")
+	}
+	return nil
+}
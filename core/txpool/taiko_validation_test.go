@@ -0,0 +1,82 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestValidateTaikoFeeCap(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Taiko = true
+	config.OntakeBlock = big.NewInt(10)
+
+	var (
+		key, _  = crypto.GenerateKey()
+		signer  = types.LatestSigner(&config)
+		minFee  = params.TaikoOntake.Params().MinBaseFee.Uint64()
+		genesis = &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+		ontake  = &types.Header{Number: big.NewInt(10), GasLimit: 30_000_000}
+	)
+	tx := func(feeCap uint64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			GasFeeCap: new(big.Int).SetUint64(feeCap),
+			GasTipCap: common.Big0,
+			Gas:       params.TxGas,
+			To:        &common.Address{},
+		})
+	}
+	tests := []struct {
+		name    string
+		config  *params.ChainConfig
+		taiko   *TaikoConfig
+		head    *types.Header
+		feeCap  uint64
+		wantErr bool
+	}{
+		{"minBaseFee", &config, nil, ontake, minFee, false},
+		{"belowMinBaseFee", &config, nil, ontake, minFee - 1, true},
+		{"belowMinBaseFeeBeforeOntake", &config, nil, genesis, 1, false},
+		{"zeroFeeCap", &config, nil, genesis, 0, true},
+		{"overriddenMinBaseFee", &config, &TaikoConfig{MinBaseFees: map[string]uint64{"ontake": minFee + 1}}, ontake, minFee, true},
+		{"genesisMinBaseFee", &config, &TaikoConfig{MinBaseFees: map[string]uint64{"genesis": 2}}, genesis, 1, true},
+		{"disabledMinBaseFee", &config, &TaikoConfig{MinBaseFees: map[string]uint64{"ontake": 0}}, ontake, 1, false},
+		{"disabledMinBaseFeeZeroFeeCap", &config, &TaikoConfig{MinBaseFees: map[string]uint64{"ontake": 0}}, ontake, 0, true},
+		{"allowZeroFeeCap", &config, &TaikoConfig{MinBaseFees: map[string]uint64{"ontake": 0}, AllowZeroFeeCap: true}, ontake, 0, false},
+		{"allowZeroFeeCapBelowMinBaseFee", &config, &TaikoConfig{AllowZeroFeeCap: true}, ontake, 0, true},
+		{"notTaiko", params.TestChainConfig, nil, ontake, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &ValidationOptions{
+				Config:  tt.config,
+				Accept:  1 << types.DynamicFeeTxType,
+				MaxSize: 128 * 1024,
+				MinTip:  new(big.Int),
+				Taiko:   tt.taiko,
+			}
+			err := ValidateTransaction(tx(tt.feeCap), tt.head, types.LatestSigner(tt.config), opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTaikoConfigValidate(t *testing.T) {
+	if err := (&TaikoConfig{MinBaseFees: map[string]uint64{"genesis": 1, "ontake": 0}}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := (&TaikoConfig{MinBaseFees: map[string]uint64{"unknown": 1}}).Validate(); err == nil {
+		t.Fatal("expected error for unknown fork")
+	}
+	if err := (&TaikoConfig{MinBaseFees: map[string]uint64{"Ontake": 1}}).Validate(); err == nil {
+		t.Fatal("expected error for upper case fork name")
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"os"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -36,8 +35,6 @@ var (
 	// blobTxMinBlobGasPrice is the big.Int version of the configured protocol
 	// parameter to avoid constructing a new big integer for every transaction.
 	blobTxMinBlobGasPrice = big.NewInt(params.BlobTxMinBlobGasprice)
-	// CHANGE(taiko): the miniumum baseFee defined in TaikoL2 (0.008847185 GWei).
-	minL2BaseFee = new(big.Int).SetUint64(8847185)
 )
 
 // ValidationOptions define certain differences between transaction validation
@@ -48,6 +45,8 @@ type ValidationOptions struct {
 	Accept  uint8    // Bitmap of transaction types that should be accepted for the calling pool
 	MaxSize uint64   // Maximum size of a transaction that the caller can meaningfully handle
 	MinTip  *big.Int // Minimum gas tip needed to allow a transaction into the caller pool
+
+	Taiko *TaikoConfig // CHANGE(taiko): fee cap checks on Taiko chains (nil = protocol defaults)
 }
 
 // ValidateTransaction is a helper method to check whether a transaction is valid
@@ -101,12 +100,8 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 		return core.ErrTipAboveFeeCap
 	}
 	// CHANGE(taiko): check gasFeeCap.
-	if os.Getenv("TAIKO_TEST") == "" {
-		if opts.Config.IsOntake(head.Number) && tx.GasFeeCap().Cmp(minL2BaseFee) < 0 {
-			return errors.New("max fee per gas is less than the minimum base fee (0.008847185 GWei)")
-		} else if tx.GasFeeCap().Cmp(common.Big0) == 0 {
-			return errors.New("max fee per gas is zero")
-		}
+	if err := validateTaikoFeeCap(tx, head, opts); err != nil {
+		return err
 	}
 	// Make sure the transaction is signed properly
 	if _, err := types.Sender(signer, tx); err != nil {
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FeeBreakdown is the fee revenue of a L2 block, or a range of L2 blocks. The base
+// fees are not burnt, but split between the treasury and the coinbase.
+type FeeBreakdown struct {
+	TreasuryIncome       *hexutil.Big   `json:"treasuryIncome"`       // Base fees paid to the treasury
+	CoinbaseBaseFeeShare *hexutil.Big   `json:"coinbaseBaseFeeShare"` // Base fees shared with the coinbase
+	PriorityFees         *hexutil.Big   `json:"priorityFees"`         // Priority fees paid to the coinbase
+	AnchorTxs            hexutil.Uint64 `json:"anchorTxs"`            // Anchor transactions, which pay no fees
+	AnchorGasUsed        hexutil.Uint64 `json:"anchorGasUsed"`        // Gas used by the anchor transactions
+}
+
+// NewFeeBreakdown returns an empty fee breakdown.
+func NewFeeBreakdown() *FeeBreakdown {
+	return &FeeBreakdown{
+		TreasuryIncome:       new(hexutil.Big),
+		CoinbaseBaseFeeShare: new(hexutil.Big),
+		PriorityFees:         new(hexutil.Big),
+	}
+}
+
+// Add adds the fees of the given breakdown.
+func (b *FeeBreakdown) Add(other *FeeBreakdown) {
+	b.TreasuryIncome.ToInt().Add(b.TreasuryIncome.ToInt(), other.TreasuryIncome.ToInt())
+	b.CoinbaseBaseFeeShare.ToInt().Add(b.CoinbaseBaseFeeShare.ToInt(), other.CoinbaseBaseFeeShare.ToInt())
+	b.PriorityFees.ToInt().Add(b.PriorityFees.ToInt(), other.PriorityFees.ToInt())
+	b.AnchorTxs += other.AnchorTxs
+	b.AnchorGasUsed += other.AnchorGasUsed
+}
+
+// BlockFeeBreakdown is the fee revenue of a single L2 block.
+type BlockFeeBreakdown struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+	*FeeBreakdown
+}
+
+// FeeBreakdownRange is the fee revenue of a range of L2 blocks, in total and per block.
+type FeeBreakdownRange struct {
+	FromBlock hexutil.Uint64       `json:"fromBlock"`
+	ToBlock   hexutil.Uint64       `json:"toBlock"`
+	Total     *FeeBreakdown        `json:"total"`
+	Blocks    []*BlockFeeBreakdown `json:"blocks"`
+}
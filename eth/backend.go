@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -41,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/eth/protocols/preconf"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -69,6 +71,9 @@ type Ethereum struct {
 	txPool     *txpool.TxPool
 	blockchain *core.BlockChain
 
+	// CHANGE(taiko): bundles sent by the searchers to the proposer.
+	bundlePool *bundlepool.BundlePool
+
 	handler *handler
 	discmix *enode.FairMix
 
@@ -235,6 +240,11 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	// CHANGE(taiko): keep the bundles to be simulated into the L2 transactions lists.
+	if chainConfig.Taiko {
+		eth.bundlePool = bundlepool.New(eth.blockchain)
+	}
+
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -247,12 +257,19 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		BloomCache:     uint64(cacheLimit),
 		EventMux:       eth.eventMux,
 		RequiredBlocks: config.RequiredBlocks,
+		// CHANGE(taiko): preconfirmation block gossip options.
+		PreconfSequencer: config.PreconfSequencer,
+		PreconfKey:       config.PreconfSigningKey,
 	}); err != nil {
 		return nil, err
 	}
 
 	eth.miner = miner.New(eth, config.Miner, eth.engine)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
+	// CHANGE(taiko): simulate the bundles into the built L2 transactions lists.
+	if eth.bundlePool != nil {
+		eth.miner.SetBundlePool(eth.bundlePool)
+	}
 
 	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, eth, nil}
 	if eth.APIBackend.allowUnprotectedTxs {
@@ -329,6 +346,7 @@ func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager }
 func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
 func (s *Ethereum) TxPool() *txpool.TxPool             { return s.txPool }
+func (s *Ethereum) BundlePool() *bundlepool.BundlePool { return s.bundlePool } // CHANGE(taiko)
 func (s *Ethereum) EventMux() *event.TypeMux           { return s.eventMux }
 func (s *Ethereum) Engine() consensus.Engine           { return s.engine }
 func (s *Ethereum) ChainDb() ethdb.Database            { return s.chainDb }
@@ -346,9 +364,22 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 	if s.config.SnapshotCache > 0 {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler))...)
 	}
+	// CHANGE(taiko): gossip the preconfirmation blocks signed by the sequencer.
+	if s.config.PreconfSequencer != (common.Address{}) {
+		protos = append(protos, preconf.MakeProtocols((*preconfHandler)(s.handler))...)
+	}
 	return protos
 }
 
+// BroadcastPreconfBlock signs a locally sealed preconfirmation block, and gossips
+// it to all the `preconf` peers. It's a no-op if the node is not the sequencer.
+func (s *Ethereum) BroadcastPreconfBlock(block *types.Block) error {
+	if s.config.PreconfSigningKey == nil {
+		return nil
+	}
+	return s.handler.BroadcastPreconfBlock(block)
+}
+
 // Start implements node.Lifecycle, starting all internal goroutines needed by the
 // Ethereum protocol implementation.
 func (s *Ethereum) Start() error {
@@ -408,6 +439,10 @@ func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Close()
+	// CHANGE(taiko): stop the bundle pool of the L2 node.
+	if s.bundlePool != nil {
+		s.bundlePool.Close()
+	}
 	s.blockchain.Stop()
 	s.engine.Close()
 
@@ -447,7 +447,17 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 		// CHANGE(taiko): create a L2 block by Taiko protocol.
 		if isTaiko {
 			// No need to check payloadAttribute here, because all its fields are
-			// marked as required.
+			// marked as required. But a L1Origin of a block proposed on L1 must tell
+			// the L1 block, since it is indexed by it, and the ID of the new block on
+			// top of the head block.
+			if l1Origin := payloadAttributes.L1Origin; l1Origin != nil && !l1Origin.IsPreconfBlock {
+				if l1Origin.BlockID == nil || l1Origin.L1BlockHeight == nil || l1Origin.L1BlockHash == (common.Hash{}) {
+					return valid(nil), engine.InvalidPayloadAttributes.With(errors.New("incomplete L1Origin"))
+				}
+				if number := new(big.Int).Add(block.Number(), common.Big1); l1Origin.BlockID.Cmp(number) != 0 {
+					return valid(nil), engine.InvalidPayloadAttributes.With(fmt.Errorf("L1Origin block ID mismatch: have %d, want %d", l1Origin.BlockID, number))
+				}
+			}
 			block, err := api.eth.Miner().SealBlockWith(
 				update.HeadBlockHash,
 				payloadAttributes.Timestamp,
@@ -480,16 +490,25 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 
 			api.localBlocks.put(id, payload)
 
-			// L1Origin **MUST NOT** be nil, it's a required field in PayloadAttributesV1.
+			// A nil L1Origin means the block is a preconfirmation block, which has not
+			// been proposed on L1 yet.
 			l1Origin := payloadAttributes.L1Origin
+			if l1Origin == nil {
+				l1Origin = &rawdb.L1Origin{BlockID: block.Number(), IsPreconfBlock: true}
+			}
 
 			// Set the block hash before inserting the L1Origin into database.
 			l1Origin.L2BlockHash = block.Hash()
 
-			// Write L1Origin.
-			rawdb.WriteL1Origin(api.eth.ChainDb(), l1Origin.BlockID, l1Origin)
-			// Write the head L1Origin.
-			rawdb.WriteHeadL1Origin(api.eth.ChainDb(), l1Origin.BlockID)
+			// Write L1Origin, its L1 block indexes and the head L1Origin.
+			api.eth.BlockChain().WriteL1Origin(l1Origin)
+
+			// Gossip the preconfirmation block to the other L2 nodes.
+			if l1Origin.IsPreconfBlock {
+				if err := api.eth.BroadcastPreconfBlock(block); err != nil {
+					log.Warn("Failed to broadcast preconfirmation block", "number", block.Number(), "hash", block.Hash(), "err", err)
+				}
+			}
 
 			return valid(&id), nil
 		}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
@@ -95,6 +96,9 @@ type SimulatedBeacon struct {
 	engineAPI          *ConsensusAPI
 	curForkchoiceState engine.ForkchoiceStateV1
 	lastBlockTime      uint64
+
+	taiko              *TaikoDevConfig      // CHANGE(taiko): seals Taiko blocks if set
+	taikoBaseFeeConfig *taiko.BaseFeeConfig // CHANGE(taiko): base fee config of the anchor transactions
 }
 
 // NewSimulatedBeacon constructs a new simulated beacon chain.
@@ -152,6 +156,10 @@ func (c *SimulatedBeacon) Stop() error {
 func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp uint64) error {
 	if timestamp <= c.lastBlockTime {
 		timestamp = c.lastBlockTime + 1
+		// CHANGE(taiko): L2 blocks may share the timestamp of their parent.
+		if c.taiko != nil {
+			timestamp = c.lastBlockTime
+		}
 	}
 	c.feeRecipientLock.Lock()
 	feeRecipient := c.feeRecipient
@@ -173,6 +181,11 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp u
 		return fmt.Errorf("failed to sync txpool: %w", err)
 	}
 
+	// CHANGE(taiko): seal the L2 blocks the way the driver does.
+	if c.taiko != nil {
+		return c.sealTaikoBlock(withdrawals, timestamp, feeRecipient)
+	}
+
 	var random [32]byte
 	rand.Read(random[:])
 	fcResponse, err := c.engineAPI.forkchoiceUpdated(c.curForkchoiceState, &engine.PayloadAttributes{
@@ -0,0 +1,269 @@
+package catalyst
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// taikoDevMaxTxListBytes is the size limit of the transactions list of a block
+// sealed in dev mode, which is the usable bytes of a single blob.
+const taikoDevMaxTxListBytes = 126_976
+
+// Base fee config of the anchor transactions of the blocks sealed in dev mode, the
+// minimum gas excess is derived from the base fee of the dev config.
+const (
+	taikoDevAdjustmentQuotient     = 8
+	taikoDevGasIssuancePerSecond   = 5_000_000
+	taikoDevMaxGasIssuancePerBlock = 600_000_000
+)
+
+// TaikoDevConfig is the config of the blocks sealed by the SimulatedBeacon on a
+// Taiko chain.
+type TaikoDevConfig struct {
+	BaseFee            *big.Int // Base fee of the sealed blocks
+	BasefeeSharingPctg uint8    // Percentage of the base fee paid to the coinbase
+}
+
+// NewTaikoSimulatedBeacon constructs a new simulated beacon chain, which seals the
+// blocks of a Taiko chain the way the driver does: through SealBlockWith, with an
+// anchor transaction and a synthetic L1Origin.
+func NewTaikoSimulatedBeacon(period uint64, eth *eth.Ethereum, config *TaikoDevConfig) (*SimulatedBeacon, error) {
+	chainConfig := eth.BlockChain().Config()
+	if !chainConfig.Taiko {
+		return nil, errors.New("not a Taiko chain")
+	}
+	if !chainConfig.IsOntake(common.Big1, 0) {
+		return nil, errors.New("the Ontake fork must be activated at genesis")
+	}
+	if _, ok := eth.Engine().(*taiko.Taiko); !ok {
+		return nil, errors.New("not the Taiko consensus engine")
+	}
+	if config.BaseFee == nil || config.BaseFee.Sign() <= 0 {
+		return nil, errors.New("invalid base fee")
+	}
+	if config.BasefeeSharingPctg > 100 {
+		return nil, fmt.Errorf("invalid basefee sharing percentage: %d", config.BasefeeSharingPctg)
+	}
+	baseFeeConfig, err := config.baseFeeConfig()
+	if err != nil {
+		return nil, err
+	}
+	sim, err := NewSimulatedBeacon(period, eth)
+	if err != nil {
+		return nil, err
+	}
+	sim.taiko, sim.taikoBaseFeeConfig = config, baseFeeConfig
+	return sim, nil
+}
+
+// baseFeeConfig returns the base fee config of the anchor transactions, whose base
+// fee is the one of the dev config as long as the gas excess of TaikoL2 stays at its
+// minimum. The gas issuance is raised above the base fee if needed, so that the
+// bonding curve doesn't skip it.
+func (config *TaikoDevConfig) baseFeeConfig() (*taiko.BaseFeeConfig, error) {
+	issuance := new(big.Int).Div(config.BaseFee, big.NewInt(taikoDevAdjustmentQuotient))
+	if issuance.Cmp(big.NewInt(taikoDevGasIssuancePerSecond)) < 0 {
+		issuance.SetInt64(taikoDevGasIssuancePerSecond)
+	} else {
+		issuance.Add(issuance, common.Big1)
+	}
+	if !issuance.IsUint64() || issuance.Uint64() > math.MaxUint32 {
+		return nil, fmt.Errorf("base fee too high: %v", config.BaseFee)
+	}
+	minGasExcess, err := taiko.MinGasExcessForBaseFee(issuance.Uint64()*taikoDevAdjustmentQuotient, config.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	return &taiko.BaseFeeConfig{
+		AdjustmentQuotient:     taikoDevAdjustmentQuotient,
+		SharingPctg:            config.BasefeeSharingPctg,
+		GasIssuancePerSecond:   uint32(issuance.Uint64()),
+		MinGasExcess:           minGasExcess,
+		MaxGasIssuancePerBlock: taikoDevMaxGasIssuancePerBlock,
+	}, nil
+}
+
+// taikoBaseFee returns the base fee of the given block, which is calculated from the
+// gas state of TaikoL2 at the end of the parent block, the same as the anchor
+// transaction does. The base fee of the dev config is used if TaikoL2 is not
+// deployed.
+func (c *SimulatedBeacon) taikoBaseFee(header, parent *types.Header, statedb *state.StateDB) (*big.Int, error) {
+	var (
+		chain   = c.eth.BlockChain()
+		context = core.NewEVMBlockContext(header, chain, nil)
+		evm     = vm.NewEVM(context, vm.TxContext{}, statedb, chain.Config(), vm.Config{})
+	)
+	gasState, err := c.eth.Engine().(*taiko.Taiko).ReadL2GasState(evm)
+	if err != nil {
+		return nil, err
+	}
+	if gasState == nil {
+		return c.taiko.BaseFee, nil
+	}
+	baseFee, _, _, err := taiko.CalcBaseFeeOntake(gasState, header.Time, uint32(parent.GasUsed), c.taikoBaseFeeConfig)
+	return baseFee, err
+}
+
+// sealTaikoBlock seals a new L2 block with the pending transactions of the pool,
+// then inserts it and marks it as canonical.
+func (c *SimulatedBeacon) sealTaikoBlock(withdrawals []*types.Withdrawal, timestamp uint64, feeRecipient common.Address) error {
+	var (
+		parent      = c.eth.BlockChain().GetHeaderByHash(c.curForkchoiceState.HeadBlockHash)
+		chainConfig = c.eth.BlockChain().Config()
+		taikoEngine = c.eth.Engine().(*taiko.Taiko)
+	)
+	if parent == nil {
+		return errors.New("parent not found")
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   feeRecipient,
+		Difficulty: common.Big0,
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       timestamp,
+	}
+	statedb, err := c.eth.BlockChain().StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+	if header.BaseFee, err = c.taikoBaseFee(header, parent, statedb); err != nil {
+		return fmt.Errorf("failed to calculate base fee: %w", err)
+	}
+	// Each block is proposed in its own synthetic L1 block, the one following the
+	// block number, and anchored to the L1 block of the block number.
+	anchor, err := taikoEngine.NewAnchorV2Tx(
+		header,
+		statedb.GetNonce(chainConfig.GoldenTouchAccount()),
+		header.Number.Uint64(),
+		taikoDevL1StateRoot(header.Number),
+		uint32(parent.GasUsed),
+		c.taikoBaseFeeConfig,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create anchor transaction: %w", err)
+	}
+	txs := types.Transactions{anchor}
+
+	// Fill the rest of the block with the pending transactions.
+	extraData := common.BigToHash(new(big.Int).SetUint64(uint64(c.taiko.BasefeeSharingPctg))).Bytes()
+	txsLists, err := c.eth.Miner().BuildTransactionsLists(
+		feeRecipient,
+		header.BaseFee,
+		extraData,
+		parent.GasLimit-chainConfig.TaikoParamsAt(header.Number, header.Time).AnchorGasLimit,
+		taikoDevMaxTxListBytes,
+		nil,
+		1,
+		nil,
+		"",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to build transactions list: %w", err)
+	}
+	if len(txsLists) != 0 {
+		txs = append(txs, txsLists[0].TxList...)
+	}
+	txList, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		return err
+	}
+
+	var random [32]byte
+	rand.Read(random[:])
+	l1Height := new(big.Int).Add(header.Number, common.Big1)
+	fcResponse, err := c.engineAPI.forkchoiceUpdated(c.curForkchoiceState, &engine.PayloadAttributes{
+		Timestamp:             timestamp,
+		SuggestedFeeRecipient: feeRecipient,
+		Withdrawals:           withdrawals,
+		Random:                random,
+		BaseFeePerGas:         header.BaseFee,
+		BlockMetadata: &engine.BlockMetadata{
+			Beneficiary: feeRecipient,
+			GasLimit:    parent.GasLimit,
+			Timestamp:   timestamp,
+			MixHash:     random,
+			TxList:      txList,
+			ExtraData:   extraData,
+		},
+		L1Origin: &rawdb.L1Origin{
+			BlockID:       header.Number,
+			L1BlockHeight: l1Height,
+			L1BlockHash:   taikoDevL1BlockHash(l1Height),
+		},
+	}, engine.PayloadV2, false)
+	if err != nil {
+		return err
+	}
+	if fcResponse.PayloadID == nil {
+		return errors.New("chain rewind prevented invocation of payload creation")
+	}
+	envelope, err := c.engineAPI.getPayload(*fcResponse.PayloadID, true)
+	if err != nil {
+		return err
+	}
+	payload := envelope.ExecutionPayload
+
+	var finalizedHash common.Hash
+	if payload.Number%devEpochLength == 0 {
+		finalizedHash = payload.BlockHash
+	} else if fh := c.finalizedBlockHash(payload.Number); fh == nil {
+		return errors.New("chain rewind interrupted calculation of finalized block hash")
+	} else {
+		finalizedHash = *fh
+	}
+	// Mark the payload as canon
+	if status, err := c.engineAPI.NewPayloadV2(*payload); err != nil {
+		return err
+	} else if status.Status != engine.VALID {
+		if status.ValidationError != nil {
+			return fmt.Errorf("invalid payload: %s", *status.ValidationError)
+		}
+		return fmt.Errorf("invalid payload status: %s", status.Status)
+	}
+	c.setCurrentState(payload.BlockHash, finalizedHash)
+
+	// Mark the block containing the payload as canonical
+	if _, err = c.engineAPI.ForkchoiceUpdatedV2(c.curForkchoiceState, nil); err != nil {
+		return err
+	}
+	c.lastBlockTime = payload.Timestamp
+	return nil
+}
+
+// taikoDevL1BlockHash returns the hash of the synthetic L1 block of the given
+// height.
+func taikoDevL1BlockHash(height *big.Int) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(height).Bytes())
+}
+
+// taikoDevL1StateRoot returns the state root of the synthetic L1 block of the given
+// height.
+func taikoDevL1StateRoot(height *big.Int) common.Hash {
+	return crypto.Keccak256Hash(taikoDevL1BlockHash(height).Bytes())
+}
+
+// DefaultTaikoDevConfig returns the dev mode config sealing the blocks at the
+// minimum base fee of the Ontake fork.
+func DefaultTaikoDevConfig() *TaikoDevConfig {
+	return &TaikoDevConfig{
+		BaseFee:            params.TaikoOntake.Params().MinBaseFee,
+		BasefeeSharingPctg: 75,
+	}
+}
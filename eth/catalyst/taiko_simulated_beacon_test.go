@@ -0,0 +1,339 @@
+package catalyst
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startTaikoSimulatedBeaconEthService(t *testing.T, genesis *core.Genesis, config *TaikoDevConfig) (*node.Node, *eth.Ethereum, *SimulatedBeacon) {
+	t.Helper()
+
+	n, err := node.New(&node.Config{
+		P2P: p2p.Config{
+			ListenAddr:  "127.0.0.1:8545",
+			NoDiscovery: true,
+			MaxPeers:    0,
+		},
+	})
+	require.NoError(t, err)
+
+	ethcfg := &ethconfig.Config{Genesis: genesis, SyncMode: downloader.FullSync, TrieTimeout: time.Minute, TrieDirtyCache: 256, TrieCleanCache: 256, Miner: miner.DefaultConfig}
+	ethservice, err := eth.New(n, ethcfg)
+	require.NoError(t, err)
+
+	simBeacon, err := NewTaikoSimulatedBeacon(0, ethservice, config)
+	require.NoError(t, err)
+
+	n.RegisterLifecycle(simBeacon)
+	require.NoError(t, n.Start())
+
+	ethservice.SetSynced()
+	return n, ethservice, simBeacon
+}
+
+func TestTaikoSimulatedBeacon(t *testing.T) {
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		config     = DefaultTaikoDevConfig()
+	)
+	node, ethService, sim := startTaikoSimulatedBeaconEthService(t, core.TaikoDeveloperGenesisBlock(10_000_000, &testAddr, nil), config)
+	defer node.Close()
+
+	var (
+		chainConfig = ethService.BlockChain().Config()
+		signer      = types.LatestSigner(chainConfig)
+		sent        []common.Hash
+	)
+	for i := 0; i < 3; i++ {
+		tx := types.MustSignNewTx(testKey, signer, &types.DynamicFeeTx{
+			ChainID:   chainConfig.ChainID,
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(params.GWei),
+			GasFeeCap: big.NewInt(2 * params.GWei),
+			Gas:       params.TxGas,
+			To:        &common.Address{},
+			Value:     big.NewInt(1000),
+		})
+		require.NoError(t, ethService.APIBackend.SendTx(context.Background(), tx))
+		sent = append(sent, tx.Hash())
+	}
+
+	// The pending transactions are included after the anchor transaction.
+	sim.Commit()
+	block := ethService.BlockChain().CurrentBlock()
+	require.Equal(t, uint64(1), block.Number.Uint64())
+	assert.Equal(t, config.BaseFee, block.BaseFee)
+	assert.Equal(t, config.BasefeeSharingPctg, chainConfig.TaikoParamsAt(block.Number, block.Time).BasefeeSharingPctg(block.Extra))
+
+	txs := ethService.BlockChain().GetBlockByHash(block.Hash()).Transactions()
+	require.Len(t, txs, 4)
+	sender, err := types.Sender(signer, txs[0])
+	require.NoError(t, err)
+	assert.Equal(t, chainConfig.GoldenTouchAccount(), sender)
+	for i, hash := range sent {
+		assert.Equal(t, hash, txs[i+1].Hash())
+	}
+	for _, receipt := range ethService.BlockChain().GetReceiptsByHash(block.Hash()) {
+		assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	}
+
+	// The block is anchored to the synthetic L1 block of its number.
+	anchor, err := ethService.Engine().(*taiko.Taiko).DecodeAnchorTx(txs[0])
+	require.NoError(t, err)
+	assert.Equal(t, block.Number.Uint64(), anchor.L1Height)
+	assert.Equal(t, taikoDevL1StateRoot(block.Number), anchor.L1StateRoot)
+	assert.Equal(t, sim.taikoBaseFeeConfig, anchor.BaseFeeConfig)
+
+	// A synthetic L1Origin is written for the block, proposed in the next L1 block.
+	l1Origin, err := rawdb.ReadL1Origin(ethService.ChainDb(), block.Number)
+	require.NoError(t, err)
+	l1Height := new(big.Int).Add(block.Number, common.Big1)
+	assert.Equal(t, block.Hash(), l1Origin.L2BlockHash)
+	assert.Equal(t, l1Height, l1Origin.L1BlockHeight)
+	assert.Equal(t, taikoDevL1BlockHash(l1Height), l1Origin.L1BlockHash)
+	assert.False(t, l1Origin.IsPreconfBlock)
+
+	head, err := rawdb.ReadHeadL1Origin(ethService.ChainDb())
+	require.NoError(t, err)
+	assert.Equal(t, block.Number, head)
+
+	// Blocks are sealed with only the anchor transaction if the pool is empty.
+	sim.Commit()
+	block = ethService.BlockChain().CurrentBlock()
+	require.Equal(t, uint64(2), block.Number.Uint64())
+
+	txs = ethService.BlockChain().GetBlockByHash(block.Hash()).Transactions()
+	require.Len(t, txs, 1)
+	assert.Equal(t, uint64(1), txs[0].Nonce())
+}
+
+// taikoL2GasStateCode returns the code of a TaikoL2 stub, which returns the storage
+// slots 0, 1 and 2 from parentGasExcess, parentGasTarget and parentTimestamp, and
+// accepts the other calls, e.g. anchorV2, without changing them.
+func taikoL2GasStateCode() []byte {
+	var (
+		getters = []string{"parentGasExcess()", "parentGasTarget()", "parentTimestamp()"}
+		code    = []byte{byte(vm.PUSH1), 0, byte(vm.CALLDATALOAD), byte(vm.PUSH1), 0xe0, byte(vm.SHR)}
+	)
+	// Each getter jumps to its slot loader, placed after the dispatcher and a STOP.
+	loaders := len(code) + 10*len(getters) + 1
+	for i, getter := range getters {
+		code = append(code, byte(vm.DUP1), byte(vm.PUSH4))
+		code = append(code, crypto.Keccak256([]byte(getter))[:4]...)
+		code = append(code, byte(vm.EQ), byte(vm.PUSH1), byte(loaders+12*i), byte(vm.JUMPI))
+	}
+	code = append(code, byte(vm.STOP))
+	for i := range getters {
+		code = append(code,
+			byte(vm.JUMPDEST), byte(vm.PUSH1), byte(i), byte(vm.SLOAD),
+			byte(vm.PUSH1), 0, byte(vm.MSTORE),
+			byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+		)
+	}
+	return code
+}
+
+// Tests that the base fee of the blocks is calculated from the gas state of TaikoL2
+// if it's predeployed, and that it's the base fee of the dev config once the gas
+// excess is down to its minimum.
+func TestTaikoSimulatedBeaconBaseFee(t *testing.T) {
+	taikoL2 := params.TaikoDevChainConfig().TaikoL2ContractAddress()
+	for _, test := range []struct {
+		name      string
+		config    *TaikoDevConfig
+		gasExcess uint64
+		atMinimum bool
+	}{
+		{"minimum", DefaultTaikoDevConfig(), 0, true},
+		{"minimumGwei", &TaikoDevConfig{BaseFee: big.NewInt(params.GWei), BasefeeSharingPctg: 50}, 0, true},
+		{"excess", DefaultTaikoDevConfig(), 2_000_000_000, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			predeploys := types.GenesisAlloc{
+				taikoL2: {
+					Code: taikoL2GasStateCode(),
+					Storage: map[common.Hash]common.Hash{
+						common.BigToHash(common.Big0): common.BigToHash(new(big.Int).SetUint64(test.gasExcess)),
+						common.BigToHash(common.Big1): common.BigToHash(big.NewInt(40_000_000)),
+						common.BigToHash(common.Big2): common.BigToHash(common.Big1),
+					},
+					Balance: common.Big0,
+				},
+			}
+			node, ethService, sim := startTaikoSimulatedBeaconEthService(t, core.TaikoDeveloperGenesisBlock(10_000_000, nil, predeploys), test.config)
+			defer node.Close()
+
+			// The block is only inserted if its base fee is the one verified against
+			// the gas state of TaikoL2.
+			sim.Commit()
+			block := ethService.BlockChain().CurrentBlock()
+			require.Equal(t, uint64(1), block.Number.Uint64())
+
+			baseFee, _, _, err := taiko.CalcBaseFeeOntake(
+				&taiko.L2GasState{GasExcess: test.gasExcess, GasTarget: 40_000_000, Timestamp: 1},
+				block.Time,
+				0,
+				sim.taikoBaseFeeConfig,
+			)
+			require.NoError(t, err)
+			assert.Equal(t, baseFee, block.BaseFee)
+			assert.Equal(t, test.atMinimum, test.config.BaseFee.Cmp(block.BaseFee) == 0)
+		})
+	}
+}
+
+// Tests that the base fee at the minimum gas excess of the anchor transactions is
+// the base fee of the dev config.
+func TestTaikoDevBaseFeeConfig(t *testing.T) {
+	for _, baseFee := range []int64{1, 1000, params.TaikoOntake.Params().MinBaseFee.Int64(), params.GWei, 30 * params.GWei} {
+		config := &TaikoDevConfig{BaseFee: big.NewInt(baseFee), BasefeeSharingPctg: 75}
+		baseFeeConfig, err := config.baseFeeConfig()
+		require.NoError(t, err, "base fee %d", baseFee)
+		assert.Equal(t, uint8(75), baseFeeConfig.SharingPctg)
+
+		// The gas excess drops to its minimum once enough gas is issued, which is
+		// never below 1 in LibEIP1559.calc1559BaseFee.
+		got, _, gasExcess, err := taiko.CalcBaseFeeOntake(&taiko.L2GasState{}, 3600, 0, baseFeeConfig)
+		require.NoError(t, err)
+		assert.Equal(t, max(baseFeeConfig.MinGasExcess, 1), gasExcess, "base fee %d", baseFee)
+		assert.Equal(t, config.BaseFee, got, "base fee %d", baseFee)
+	}
+	_, err := (&TaikoDevConfig{BaseFee: big.NewInt(100 * params.GWei)}).baseFeeConfig()
+	assert.Error(t, err)
+}
+
+func TestNewTaikoSimulatedBeaconInvalidConfig(t *testing.T) {
+	n, err := node.New(&node.Config{})
+	require.NoError(t, err)
+	defer n.Close()
+
+	ethservice, err := eth.New(n, &ethconfig.Config{Genesis: core.DeveloperGenesisBlock(10_000_000, nil), Miner: miner.DefaultConfig})
+	require.NoError(t, err)
+
+	_, err = NewTaikoSimulatedBeacon(0, ethservice, DefaultTaikoDevConfig())
+	assert.ErrorContains(t, err, "not a Taiko chain")
+}
+
+// Tests that the L1Origin of a block proposed on L1 is rejected unless it tells the
+// L1 block and the ID of the block.
+func TestTaikoForkchoiceUpdatedInvalidL1Origin(t *testing.T) {
+	testAddr := common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+	node, ethService, sim := startTaikoSimulatedBeaconEthService(t, core.TaikoDeveloperGenesisBlock(10_000_000, &testAddr, nil), DefaultTaikoDevConfig())
+	defer node.Close()
+
+	var (
+		parent    = ethService.BlockChain().CurrentBlock()
+		number    = new(big.Int).Add(parent.Number, common.Big1)
+		timestamp = parent.Time + 1
+	)
+	txList, err := rlp.EncodeToBytes(types.Transactions{})
+	require.NoError(t, err)
+
+	for name, test := range map[string]struct {
+		l1Origin *rawdb.L1Origin
+		want     string
+	}{
+		"no L1 height":    {&rawdb.L1Origin{BlockID: number, L1BlockHash: common.Hash{0x01}}, "incomplete L1Origin"},
+		"no L1 hash":      {&rawdb.L1Origin{BlockID: number, L1BlockHeight: common.Big1}, "incomplete L1Origin"},
+		"no block ID":     {&rawdb.L1Origin{L1BlockHeight: common.Big1, L1BlockHash: common.Hash{0x01}}, "incomplete L1Origin"},
+		"wrong block ID":  {&rawdb.L1Origin{BlockID: new(big.Int).Add(number, common.Big1), L1BlockHeight: common.Big1, L1BlockHash: common.Hash{0x01}}, "block ID mismatch"},
+		"parent block ID": {&rawdb.L1Origin{BlockID: parent.Number, L1BlockHeight: common.Big1, L1BlockHash: common.Hash{0x01}}, "block ID mismatch"},
+	} {
+		_, err := sim.engineAPI.forkchoiceUpdated(sim.curForkchoiceState, &engine.PayloadAttributes{
+			Timestamp:             timestamp,
+			SuggestedFeeRecipient: testAddr,
+			Withdrawals:           []*types.Withdrawal{},
+			BaseFeePerGas:         parent.BaseFee,
+			BlockMetadata: &engine.BlockMetadata{
+				Beneficiary: testAddr,
+				GasLimit:    parent.GasLimit,
+				Timestamp:   timestamp,
+				TxList:      txList,
+				ExtraData:   make([]byte, 32),
+			},
+			L1Origin: test.l1Origin,
+		}, engine.PayloadV2, false)
+		var apiErr *engine.EngineAPIError
+		require.ErrorAs(t, err, &apiErr, name)
+		assert.Equal(t, engine.InvalidPayloadAttributes.ErrorCode(), apiErr.ErrorCode(), name)
+		assert.Contains(t, fmt.Sprint(apiErr.ErrorData()), test.want, name)
+	}
+
+	// No L1Origin is written for the rejected blocks.
+	l1Origin, err := rawdb.ReadL1Origin(ethService.ChainDb(), number)
+	require.NoError(t, err)
+	assert.Nil(t, l1Origin)
+}
+
+// Tests that the fees of the transactions lists are estimated with the extradata of
+// the proposed block, or the one of the head block if it's not given.
+func TestTaikoTxPoolContentExtraData(t *testing.T) {
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		config     = DefaultTaikoDevConfig()
+	)
+	node, ethService, sim := startTaikoSimulatedBeaconEthService(t, core.TaikoDeveloperGenesisBlock(10_000_000, &testAddr, nil), config)
+	defer node.Close()
+
+	// Seal a block with the configured sharing percentage.
+	sim.Commit()
+
+	chainConfig := ethService.BlockChain().Config()
+	tx := types.MustSignNewTx(testKey, types.LatestSigner(chainConfig), &types.DynamicFeeTx{
+		ChainID:   chainConfig.ChainID,
+		Nonce:     0,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(2 * params.GWei),
+		Gas:       params.TxGas,
+		To:        &common.Address{},
+		Value:     big.NewInt(1000),
+	})
+	require.NoError(t, ethService.APIBackend.SendTx(context.Background(), tx))
+
+	var (
+		api      = eth.NewTaikoAuthAPIBackend(ethService)
+		baseFee  = config.BaseFee
+		totalFee = new(big.Int).Mul(baseFee, new(big.Int).SetUint64(params.TxGas))
+	)
+	for _, test := range []struct {
+		extraData *hexutil.Bytes
+		pctg      uint8
+	}{
+		{&hexutil.Bytes{0}, 0},
+		{&hexutil.Bytes{50}, 50},
+		{nil, config.BasefeeSharingPctg},
+	} {
+		txLists, err := api.TxPoolContent(testAddr, baseFee, 10_000_000, 131072, nil, 1, nil, nil, test.extraData)
+		require.NoError(t, err)
+		require.Len(t, txLists, 1)
+
+		toCoinbase := new(big.Int).Div(new(big.Int).Mul(totalFee, big.NewInt(int64(test.pctg))), big.NewInt(100))
+		assert.Equal(t, toCoinbase, txLists[0].BaseFeeToCoinbase, "pctg %d", test.pctg)
+		assert.Equal(t, new(big.Int).Sub(totalFee, toCoinbase), txLists[0].BaseFeeToTreasury, "pctg %d", test.pctg)
+	}
+}
@@ -18,6 +18,7 @@
 package ethconfig
 
 import (
+	"crypto/ecdsa"
 	"errors"
 	"time"
 
@@ -157,6 +158,13 @@ type Config struct {
 
 	// OverrideVerkle (TODO: remove after the fork)
 	OverrideVerkle *uint64 `toml:",omitempty"`
+
+	// CHANGE(taiko): preconfirmation block gossip options.
+	PreconfSequencer  common.Address    `toml:",omitempty"` // Sequencer whose signed preconfirmation blocks are accepted
+	PreconfSigningKey *ecdsa.PrivateKey `toml:"-"`          // Key to sign the local preconfirmation blocks with
+
+	// CHANGE(taiko): whether the taiko and taikoAuth RPC APIs are left unregistered.
+	DisableTaikoAPIs bool `toml:",omitempty"`
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
@@ -3,6 +3,7 @@
 package ethconfig
 
 import (
+	"crypto/ecdsa"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -50,8 +51,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCGasCap               uint64
 		RPCEVMTimeout           time.Duration
 		RPCTxFeeCap             float64
-		OverrideCancun          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		OverrideCancun          *uint64           `toml:",omitempty"`
+		OverrideVerkle          *uint64           `toml:",omitempty"`
+		PreconfSequencer        common.Address    `toml:",omitempty"`
+		PreconfSigningKey       *ecdsa.PrivateKey `toml:"-"`
+		DisableTaikoAPIs        bool              `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -89,6 +93,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
 	enc.OverrideCancun = c.OverrideCancun
 	enc.OverrideVerkle = c.OverrideVerkle
+	enc.PreconfSequencer = c.PreconfSequencer
+	enc.PreconfSigningKey = c.PreconfSigningKey
+	enc.DisableTaikoAPIs = c.DisableTaikoAPIs
 	return &enc, nil
 }
 
@@ -128,8 +135,11 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCGasCap               *uint64
 		RPCEVMTimeout           *time.Duration
 		RPCTxFeeCap             *float64
-		OverrideCancun          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		OverrideCancun          *uint64           `toml:",omitempty"`
+		OverrideVerkle          *uint64           `toml:",omitempty"`
+		PreconfSequencer        *common.Address   `toml:",omitempty"`
+		PreconfSigningKey       *ecdsa.PrivateKey `toml:"-"`
+		DisableTaikoAPIs        *bool             `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -240,5 +250,14 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.OverrideVerkle != nil {
 		c.OverrideVerkle = dec.OverrideVerkle
 	}
+	if dec.PreconfSequencer != nil {
+		c.PreconfSequencer = *dec.PreconfSequencer
+	}
+	if dec.PreconfSigningKey != nil {
+		c.PreconfSigningKey = dec.PreconfSigningKey
+	}
+	if dec.DisableTaikoAPIs != nil {
+		c.DisableTaikoAPIs = *dec.DisableTaikoAPIs
+	}
 	return nil
 }
@@ -17,6 +17,7 @@
 package eth
 
 import (
+	"crypto/ecdsa"
 	"errors"
 	"math"
 	"math/big"
@@ -93,6 +94,10 @@ type handlerConfig struct {
 	BloomCache     uint64                 // Megabytes to alloc for snap sync bloom
 	EventMux       *event.TypeMux         // Legacy event mux, deprecate for `feed`
 	RequiredBlocks map[uint64]common.Hash // Hard coded map of required block hashes for sync challenges
+
+	// CHANGE(taiko): preconfirmation block gossip options.
+	PreconfSequencer common.Address    // Sequencer whose signed preconfirmation blocks are accepted
+	PreconfKey       *ecdsa.PrivateKey // Key to sign the local preconfirmation blocks with
 }
 
 type handler struct {
@@ -118,6 +123,11 @@ type handler struct {
 
 	requiredBlocks map[uint64]common.Hash
 
+	// CHANGE(taiko): preconfirmation block gossip.
+	preconfSequencer common.Address
+	preconfKey       *ecdsa.PrivateKey
+	preconfPeers     *preconfPeerSet
+
 	// channels for fetcher, syncer, txsyncLoop
 	quitSync chan struct{}
 
@@ -144,8 +154,12 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		peers:          newPeerSet(),
 		requiredBlocks: config.RequiredBlocks,
 		quitSync:       make(chan struct{}),
-		handlerDoneCh:  make(chan struct{}),
-		handlerStartCh: make(chan struct{}),
+		// CHANGE(taiko): preconfirmation block gossip.
+		preconfSequencer: config.PreconfSequencer,
+		preconfKey:       config.PreconfKey,
+		preconfPeers:     newPreconfPeerSet(),
+		handlerDoneCh:    make(chan struct{}),
+		handlerStartCh:   make(chan struct{}),
 	}
 	if config.Sync == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
@@ -0,0 +1,244 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/preconf"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+var (
+	// errUnexpectedPreconfSigner is returned if a gossiped preconfirmation block is
+	// not signed by the configured sequencer.
+	errUnexpectedPreconfSigner = errors.New("unexpected preconfirmation block signer")
+
+	// errPreconfSigningKeyNotSet is returned if the local node is asked to gossip a
+	// preconfirmation block without a signing key.
+	errPreconfSigningKeyNotSet = errors.New("preconfirmation signing key not set")
+
+	// errUnknownPreconfParent is returned if the parent of a gossiped preconfirmation
+	// block is not canonical, the peer might just be ahead of or behind the local chain.
+	errUnknownPreconfParent = errors.New("unknown preconfirmation block parent")
+
+	// errKnownPreconfBlock is returned if a gossiped preconfirmation block is known,
+	// but not canonical, it has been replaced by another preconfirmation block.
+	errKnownPreconfBlock = errors.New("known non-canonical preconfirmation block")
+
+	// errPreconfBlockProposed is returned if a gossiped preconfirmation block is at
+	// or below the head L1Origin, which is never replaced by a gossiped block.
+	errPreconfBlockProposed = errors.New("preconfirmation block already proposed on L1")
+
+	// errInvalidPreconfBlock is returned if a gossiped preconfirmation block can't
+	// be inserted into the chain.
+	errInvalidPreconfBlock = errors.New("invalid preconfirmation block")
+)
+
+// preconfHandler implements the preconf.Backend interface to handle the gossiped
+// preconfirmation blocks.
+type preconfHandler handler
+
+func (h *preconfHandler) Chain() *core.BlockChain { return h.chain }
+
+// RunPeer is invoked when a peer joins on the `preconf` protocol.
+func (h *preconfHandler) RunPeer(peer *preconf.Peer, hand preconf.Handler) error {
+	return (*handler)(h).runPreconfPeer(peer, hand)
+}
+
+// PeerInfo retrieves all known `preconf` information about a peer.
+func (h *preconfHandler) PeerInfo(id enode.ID) interface{} {
+	if p := h.preconfPeers.peer(id.String()); p != nil {
+		return &struct {
+			Version uint `json:"version"`
+		}{p.Version()}
+	}
+	return nil
+}
+
+// Handle is invoked from a peer's message handler when it receives a new remote
+// message that the handler couldn't consume and serve itself.
+func (h *preconfHandler) Handle(peer *preconf.Peer, packet preconf.Packet) error {
+	switch packet := packet.(type) {
+	case *preconf.PreconfBlockPacket:
+		return h.handlePreconfBlock(peer, packet)
+
+	default:
+		return fmt.Errorf("unexpected preconf packet type: %T", packet)
+	}
+}
+
+// handlePreconfBlock verifies the sequencer's signature of a gossiped block, then
+// inserts it as the canonical head and relays it to the other peers. Blocks whose
+// parent is not canonical are dropped silently, since the peer might just be ahead
+// of or behind the local chain, but the peers sending invalid blocks, or blocks
+// already proposed on L1, are disconnected.
+func (h *preconfHandler) handlePreconfBlock(peer *preconf.Peer, packet *preconf.PreconfBlockPacket) error {
+	signer, err := packet.Signer(h.chain.Config().ChainID)
+	if err != nil {
+		return err
+	}
+	if signer != h.preconfSequencer {
+		return fmt.Errorf("%w: have %s, want %s", errUnexpectedPreconfSigner, signer, h.preconfSequencer)
+	}
+	// The canonical blocks are relayed by several peers, and might have been proposed
+	// on L1 in the meantime.
+	block := packet.Block
+	if h.chain.GetCanonicalHash(block.NumberU64()) == block.Hash() {
+		return nil
+	}
+	if err := h.insertPreconfBlock(block); err != nil {
+		if errors.Is(err, errUnknownPreconfParent) || errors.Is(err, errKnownPreconfBlock) {
+			peer.Log().Debug("Dropped preconfirmation block", "number", block.Number(), "hash", block.Hash(), "err", err)
+			return nil
+		}
+		return err
+	}
+	(*handler)(h).broadcastPreconfBlock(packet)
+
+	return nil
+}
+
+// insertPreconfBlock inserts a preconfirmation block on top of the canonical chain,
+// replacing the preconfirmation blocks at or above its height. L2 blocks which are
+// already proposed on L1 are never replaced, the L1-confirmed version of a block is
+// always reorged onto by the engine API instead.
+func (h *preconfHandler) insertPreconfBlock(block *types.Block) error {
+	number := block.NumberU64()
+	if number == 0 {
+		return fmt.Errorf("%w: genesis block", errInvalidPreconfBlock)
+	}
+	headL1OriginID, err := rawdb.ReadHeadL1Origin(h.database)
+	if err != nil {
+		return err
+	}
+	if headL1OriginID != nil && number <= headL1OriginID.Uint64() {
+		return fmt.Errorf("%w: head L1Origin %d", errPreconfBlockProposed, headL1OriginID)
+	}
+	if h.chain.HasBlock(block.Hash(), number) {
+		return errKnownPreconfBlock
+	}
+	if parent := h.chain.GetHeaderByNumber(number - 1); parent == nil || parent.Hash() != block.ParentHash() {
+		return fmt.Errorf("%w: %s", errUnknownPreconfParent, block.ParentHash())
+	}
+	if _, err := h.chain.InsertBlockWithoutSetHead(block, false); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidPreconfBlock, err)
+	}
+	if _, err := h.chain.SetCanonical(block); err != nil {
+		return err
+	}
+	h.chain.WriteL1Origin(&rawdb.L1Origin{
+		BlockID:        block.Number(),
+		L2BlockHash:    block.Hash(),
+		IsPreconfBlock: true,
+	})
+	log.Info("Imported gossiped preconfirmation block", "number", number, "hash", block.Hash())
+
+	return nil
+}
+
+// runPreconfPeer registers a `preconf` peer into the preconfirmation peer set and
+// starts handling inbound messages.
+func (h *handler) runPreconfPeer(peer *preconf.Peer, handler preconf.Handler) error {
+	if !h.incHandlers() {
+		return p2p.DiscQuitting
+	}
+	defer h.decHandlers()
+
+	if err := h.preconfPeers.register(peer); err != nil {
+		peer.Log().Debug("Preconfirmation peer registration failed", "err", err)
+		return err
+	}
+	defer h.preconfPeers.unregister(peer.ID())
+
+	return handler(peer)
+}
+
+// BroadcastPreconfBlock signs a locally sealed preconfirmation block with the
+// sequencer's key, and gossips it to all the `preconf` peers.
+func (h *handler) BroadcastPreconfBlock(block *types.Block) error {
+	if h.preconfKey == nil {
+		return errPreconfSigningKeyNotSet
+	}
+	packet, err := preconf.NewPreconfBlockPacket(block, h.chain.Config().ChainID, h.preconfKey)
+	if err != nil {
+		return err
+	}
+	h.broadcastPreconfBlock(packet)
+
+	return nil
+}
+
+// broadcastPreconfBlock propagates a signed preconfirmation block to all the
+// `preconf` peers which are not known to have it yet.
+func (h *handler) broadcastPreconfBlock(packet *preconf.PreconfBlockPacket) {
+	hash := packet.Block.Hash()
+	for _, peer := range h.preconfPeers.peersWithoutBlock(hash) {
+		if err := peer.SendPreconfBlock(packet); err != nil {
+			peer.Log().Debug("Failed to send preconfirmation block", "hash", hash, "err", err)
+		}
+	}
+}
+
+// preconfPeerSet represents the collection of active peers participating in the
+// `preconf` protocol.
+type preconfPeerSet struct {
+	peers map[string]*preconf.Peer
+	lock  sync.RWMutex
+}
+
+// newPreconfPeerSet creates a new peer set to track the active `preconf` peers.
+func newPreconfPeerSet() *preconfPeerSet {
+	return &preconfPeerSet{
+		peers: make(map[string]*preconf.Peer),
+	}
+}
+
+// register injects a new `preconf` peer into the working set.
+func (ps *preconfPeerSet) register(peer *preconf.Peer) error {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if _, ok := ps.peers[peer.ID()]; ok {
+		return errPeerAlreadyRegistered
+	}
+	ps.peers[peer.ID()] = peer
+	return nil
+}
+
+// unregister removes a remote peer from the active set.
+func (ps *preconfPeerSet) unregister(id string) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	delete(ps.peers, id)
+}
+
+// peer retrieves the registered peer with the given id.
+func (ps *preconfPeerSet) peer(id string) *preconf.Peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	return ps.peers[id]
+}
+
+// peersWithoutBlock retrieves a list of peers that do not have a given block in
+// their set of known hashes.
+func (ps *preconfPeerSet) peersWithoutBlock(hash common.Hash) []*preconf.Peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*preconf.Peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if !p.KnownBlock(hash) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
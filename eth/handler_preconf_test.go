@@ -0,0 +1,209 @@
+package eth
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/preconf"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testPreconfKey is the private key of the sequencer signing preconfirmation blocks.
+var testPreconfKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+
+// newTestPreconfHandler creates a handler accepting the preconfirmation blocks of
+// the test sequencer, the blocks are signed with the given key if it's not nil.
+func newTestPreconfHandler(key *ecdsa.PrivateKey) *testHandler {
+	handler := newTestHandler()
+	handler.handler.preconfSequencer = crypto.PubkeyToAddress(testPreconfKey.PublicKey)
+	handler.handler.preconfKey = key
+	return handler
+}
+
+// newTestPreconfBlocks generates a chain on top of the genesis block of the test
+// handlers, the given seed distinguishes the competing chains.
+func newTestPreconfBlocks(n int, seed byte) []*types.Block {
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{testAddr: {Balance: big.NewInt(1000000)}},
+	}
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), n, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(common.Address{seed})
+	})
+	return blocks
+}
+
+// connectPreconfPeers interconnects two handlers on the `preconf` protocol, and
+// waits until both sides registered the other one.
+func connectPreconfPeers(t *testing.T, a, b *testHandler, aID, bID enode.ID) {
+	t.Helper()
+
+	aPipe, bPipe := p2p.MsgPipe()
+	t.Cleanup(func() {
+		aPipe.Close()
+		bPipe.Close()
+	})
+	aPeer := preconf.NewPeer(preconf.PRECONF1, p2p.NewPeerPipe(bID, "", nil, aPipe), aPipe)
+	bPeer := preconf.NewPeer(preconf.PRECONF1, p2p.NewPeerPipe(aID, "", nil, bPipe), bPipe)
+
+	go a.handler.runPreconfPeer(aPeer, func(peer *preconf.Peer) error {
+		return preconf.Handle((*preconfHandler)(a.handler), peer)
+	})
+	go b.handler.runPreconfPeer(bPeer, func(peer *preconf.Peer) error {
+		return preconf.Handle((*preconfHandler)(b.handler), peer)
+	})
+	for a.handler.preconfPeers.peer(aPeer.ID()) == nil || b.handler.preconfPeers.peer(bPeer.ID()) == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitForHead waits until the canonical head of the handler is the given block.
+func waitForHead(t *testing.T, h *testHandler, block *types.Block) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if h.chain.CurrentBlock().Hash() == block.Hash() {
+			return
+		}
+	}
+	t.Fatalf("Head mismatch: have %d, want %d", h.chain.CurrentBlock().Number, block.Number())
+}
+
+// Tests that the preconfirmation blocks sealed by the sequencer are gossiped to
+// all the connected nodes, and relayed further by them.
+func TestPreconfBlockPropagation(t *testing.T) {
+	t.Parallel()
+
+	// Close the handlers after the peer connections, which are torn down in the
+	// test cleanup as well.
+	source := newTestPreconfHandler(testPreconfKey)
+	t.Cleanup(source.close)
+
+	sinks := make([]*testHandler, 2)
+	for i := range sinks {
+		sinks[i] = newTestPreconfHandler(nil)
+		t.Cleanup(sinks[i].close)
+	}
+	// Connect the nodes in a line, source <-> sink #0 <-> sink #1.
+	connectPreconfPeers(t, source, sinks[0], enode.ID{1}, enode.ID{2})
+	connectPreconfPeers(t, sinks[0], sinks[1], enode.ID{2}, enode.ID{3})
+
+	blocks := newTestPreconfBlocks(2, 0x01)
+	for _, block := range blocks {
+		if err := source.handler.BroadcastPreconfBlock(block); err != nil {
+			t.Fatalf("Failed to broadcast preconfirmation block: %v", err)
+		}
+		for _, sink := range sinks {
+			waitForHead(t, sink, block)
+		}
+	}
+	for i, sink := range sinks {
+		l1Origin, err := rawdb.ReadL1Origin(sink.db, blocks[1].Number())
+		if err != nil || l1Origin == nil || !l1Origin.IsPreconfBlock || l1Origin.L2BlockHash != blocks[1].Hash() {
+			t.Errorf("sink %d: preconfirmation L1Origin mismatch: have %v, err %v", i, l1Origin, err)
+		}
+		headPreconfID, err := rawdb.ReadHeadPreconfL1Origin(sink.db)
+		if err != nil || headPreconfID == nil || headPreconfID.Cmp(blocks[1].Number()) != 0 {
+			t.Errorf("sink %d: head preconfirmation L1Origin mismatch: have %v, err %v", i, headPreconfID, err)
+		}
+	}
+	// Followers can't gossip blocks by themselves.
+	if err := sinks[0].handler.BroadcastPreconfBlock(blocks[1]); !errors.Is(err, errPreconfSigningKeyNotSet) {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errPreconfSigningKeyNotSet)
+	}
+}
+
+// Tests that preconfirmation blocks not signed by the configured sequencer for the
+// local chain, or invalid ones, are rejected, and the block proposed on L1 is never
+// replaced by a gossiped one.
+func TestPreconfBlockValidation(t *testing.T) {
+	t.Parallel()
+
+	sink := newTestPreconfHandler(nil)
+	defer sink.close()
+
+	pipe, _ := p2p.MsgPipe()
+	defer pipe.Close()
+	peer := preconf.NewPeer(preconf.PRECONF1, p2p.NewPeerPipe(enode.ID{1}, "", nil, pipe), pipe)
+
+	var (
+		blocks = newTestPreconfBlocks(2, 0x01)
+		forked = newTestPreconfBlocks(2, 0x02)
+	)
+	// Blocks signed by an unknown key are rejected, dropping the peer.
+	key, _ := crypto.GenerateKey()
+	packet, _ := preconf.NewPreconfBlockPacket(blocks[0], sink.chain.Config().ChainID, key)
+	if err := (*preconfHandler)(sink.handler).Handle(peer, packet); !errors.Is(err, errUnexpectedPreconfSigner) {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errUnexpectedPreconfSigner)
+	}
+	if head := sink.chain.CurrentBlock(); head.Number.Uint64() != 0 {
+		t.Fatalf("Head mismatch: have %d, want 0", head.Number)
+	}
+
+	// Insert a preconfirmation block, then reorg onto the L1-confirmed version of it
+	// like the engine API does.
+	packet, _ = preconf.NewPreconfBlockPacket(blocks[0], sink.chain.Config().ChainID, testPreconfKey)
+	if err := (*preconfHandler)(sink.handler).Handle(peer, packet); err != nil {
+		t.Fatalf("Failed to handle preconfirmation block: %v", err)
+	}
+	waitForHead(t, sink, blocks[0])
+
+	if _, err := sink.chain.InsertBlockWithoutSetHead(forked[0], false); err != nil {
+		t.Fatalf("Failed to insert block: %v", err)
+	}
+	if _, err := sink.chain.SetCanonical(forked[0]); err != nil {
+		t.Fatalf("Failed to set canonical head: %v", err)
+	}
+	sink.chain.WriteL1Origin(&rawdb.L1Origin{
+		BlockID:       forked[0].Number(),
+		L2BlockHash:   forked[0].Hash(),
+		L1BlockHeight: big.NewInt(1),
+		L1BlockHash:   common.Hash{0x01},
+	})
+	if headPreconfID, _ := rawdb.ReadHeadPreconfL1Origin(sink.db); headPreconfID != nil {
+		t.Fatalf("Head preconfirmation L1Origin not rolled back: %d", headPreconfID)
+	}
+
+	// The gossiped version of the L1-confirmed block is rejected, dropping the peer,
+	// and its descendants are ignored.
+	packet, _ = preconf.NewPreconfBlockPacket(blocks[0], sink.chain.Config().ChainID, testPreconfKey)
+	if err := (*preconfHandler)(sink.handler).Handle(peer, packet); !errors.Is(err, errPreconfBlockProposed) {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errPreconfBlockProposed)
+	}
+	packet, _ = preconf.NewPreconfBlockPacket(blocks[1], sink.chain.Config().ChainID, testPreconfKey)
+	if err := (*preconfHandler)(sink.handler).Handle(peer, packet); err != nil {
+		t.Fatalf("Failed to handle preconfirmation block: %v", err)
+	}
+	waitForHead(t, sink, forked[0])
+
+	// Invalid blocks are rejected, dropping the peer.
+	header := forked[1].Header()
+	header.Root = common.Hash{0x01}
+	packet, _ = preconf.NewPreconfBlockPacket(forked[1].WithSeal(header), sink.chain.Config().ChainID, testPreconfKey)
+	if err := (*preconfHandler)(sink.handler).Handle(peer, packet); !errors.Is(err, errInvalidPreconfBlock) {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errInvalidPreconfBlock)
+	}
+	// Blocks signed for another chain are rejected too.
+	packet, _ = preconf.NewPreconfBlockPacket(forked[1], big.NewInt(167000), testPreconfKey)
+	if err := (*preconfHandler)(sink.handler).Handle(peer, packet); !errors.Is(err, errUnexpectedPreconfSigner) {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errUnexpectedPreconfSigner)
+	}
+
+	// Preconfirmation blocks on top of the L1-confirmed block are accepted.
+	packet, _ = preconf.NewPreconfBlockPacket(forked[1], sink.chain.Config().ChainID, testPreconfKey)
+	if err := (*preconfHandler)(sink.handler).Handle(peer, packet); err != nil {
+		t.Fatalf("Failed to handle preconfirmation block: %v", err)
+	}
+	waitForHead(t, sink, forked[1])
+}
@@ -0,0 +1,111 @@
+package preconf
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// testBackend is a mock backend recording the delivered packets.
+type testBackend struct {
+	packets []Packet
+}
+
+func (b *testBackend) Chain() *core.BlockChain                   { return nil }
+func (b *testBackend) RunPeer(peer *Peer, handler Handler) error { return handler(peer) }
+func (b *testBackend) PeerInfo(id enode.ID) interface{}          { return nil }
+
+func (b *testBackend) Handle(peer *Peer, packet Packet) error {
+	b.packets = append(b.packets, packet)
+	return nil
+}
+
+func newTestBlock() *types.Block {
+	return types.NewBlockWithHeader(&types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(1),
+	})
+}
+
+// Tests that the sequencer of a preconfirmation block can be recovered from the
+// envelope, and malformed signatures are rejected.
+func TestPreconfBlockPacketSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	chainID := big.NewInt(167000)
+	packet, err := NewPreconfBlockPacket(newTestBlock(), chainID, key)
+	if err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	signer, err := packet.Signer(chainID)
+	if err != nil {
+		t.Fatalf("Failed to recover signer: %v", err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); signer != want {
+		t.Fatalf("Signer mismatch: have %s, want %s", signer, want)
+	}
+
+	// A different block must not be attributed to the sequencer.
+	other := &PreconfBlockPacket{
+		Block:     types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2)}),
+		Signature: packet.Signature,
+	}
+	if signer, err := other.Signer(chainID); err == nil && signer == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("Signature of another block accepted")
+	}
+	// The envelope of another network sharing the key must not be accepted.
+	if signer, err := packet.Signer(big.NewInt(167009)); err == nil && signer == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("Signature of another chain accepted")
+	}
+	// The bare block hash is not signed.
+	hash := packet.Block.Hash()
+	if pubkey, err := crypto.SigToPub(hash[:], packet.Signature); err == nil && crypto.PubkeyToAddress(*pubkey) == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("Block hash signed without domain separation")
+	}
+	truncated := &PreconfBlockPacket{Block: packet.Block, Signature: packet.Signature[:64]}
+	if _, err := truncated.Signer(chainID); !errors.Is(err, errInvalidBlock) {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errInvalidBlock)
+	}
+}
+
+// Tests that gossiped preconfirmation blocks are delivered to the backend and
+// marked as known by the sending peer.
+func TestHandlePreconfBlock(t *testing.T) {
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	var (
+		backend = new(testBackend)
+		peer    = NewPeer(PRECONF1, p2p.NewPeerPipe(enode.ID{1}, "", nil, app), app)
+	)
+	key, _ := crypto.GenerateKey()
+	packet, _ := NewPreconfBlockPacket(newTestBlock(), big.NewInt(1), key)
+
+	go p2p.Send(net, PreconfBlockMsg, packet)
+	if err := HandleMessage(backend, peer); err != nil {
+		t.Fatalf("Failed to handle message: %v", err)
+	}
+	if len(backend.packets) != 1 {
+		t.Fatalf("Delivered packets mismatch: have %d, want 1", len(backend.packets))
+	}
+	delivered := backend.packets[0].(*PreconfBlockPacket)
+	if delivered.Block.Hash() != packet.Block.Hash() {
+		t.Fatalf("Delivered block mismatch: have %x, want %x", delivered.Block.Hash(), packet.Block.Hash())
+	}
+	if !peer.KnownBlock(packet.Block.Hash()) {
+		t.Fatalf("Delivered block not marked as known")
+	}
+
+	// Unknown messages tear down the connection.
+	go p2p.Send(net, PreconfBlockMsg+1, []byte{})
+	if err := HandleMessage(backend, peer); !errors.Is(err, errInvalidMsgCode) {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errInvalidMsgCode)
+	}
+}
@@ -0,0 +1,76 @@
+package preconf
+
+import (
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// maxKnownBlocks is the maximum block hashes to keep in the known list before
+// starting to randomly evict them.
+const maxKnownBlocks = 1024
+
+// Peer is a collection of relevant information we have about a `preconf` peer.
+type Peer struct {
+	id string // Unique ID for the peer, cached
+
+	*p2p.Peer                   // The embedded P2P package peer
+	rw        p2p.MsgReadWriter // Input/output streams for preconf
+	version   uint              // Protocol version negotiated
+
+	knownBlocks mapset.Set[common.Hash] // Set of block hashes known to be known by this peer
+
+	logger log.Logger // Contextual logger with the peer id injected
+}
+
+// NewPeer creates a wrapper for a network connection and negotiated  protocol
+// version.
+func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+	id := p.ID().String()
+	return &Peer{
+		id:          id,
+		Peer:        p,
+		rw:          rw,
+		version:     version,
+		knownBlocks: mapset.NewSet[common.Hash](),
+		logger:      log.New("peer", id[:8]),
+	}
+}
+
+// ID retrieves the peer's unique identifier.
+func (p *Peer) ID() string {
+	return p.id
+}
+
+// Version retrieves the peer's negotiated `preconf` protocol version.
+func (p *Peer) Version() uint {
+	return p.version
+}
+
+// Log overrides the P2P logger with the higher level one containing only the id.
+func (p *Peer) Log() log.Logger {
+	return p.logger
+}
+
+// KnownBlock returns whether peer is known to already have a block.
+func (p *Peer) KnownBlock(hash common.Hash) bool {
+	return p.knownBlocks.Contains(hash)
+}
+
+// markBlock marks a block as known for the peer, ensuring that the block will
+// never be propagated to this particular peer.
+func (p *Peer) markBlock(hash common.Hash) {
+	// If we reached the memory allowance, drop a previously known block hash
+	for p.knownBlocks.Cardinality() >= maxKnownBlocks {
+		p.knownBlocks.Pop()
+	}
+	p.knownBlocks.Add(hash)
+}
+
+// SendPreconfBlock propagates a signed preconfirmation block to the remote peer,
+// and marks it as known.
+func (p *Peer) SendPreconfBlock(packet *PreconfBlockPacket) error {
+	p.markBlock(packet.Block.Hash())
+	return p2p.Send(p.rw, PreconfBlockMsg, packet)
+}
@@ -0,0 +1,98 @@
+package preconf
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	PRECONF1 = 1
+)
+
+// ProtocolName is the official short name of the `preconf` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "preconf"
+
+// ProtocolVersions are the supported versions of the `preconf` protocol (first
+// is primary).
+var ProtocolVersions = []uint{PRECONF1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{PRECONF1: 1}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 10 * 1024 * 1024
+
+const (
+	PreconfBlockMsg = 0x00
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+	errInvalidBlock   = errors.New("invalid preconfirmation block")
+)
+
+// Packet represents a p2p message in the `preconf` protocol.
+type Packet interface {
+	Name() string // Name returns a string corresponding to the message type.
+	Kind() byte   // Kind returns the message type.
+}
+
+// signingDomain separates the signatures of the preconfirmation blocks from any
+// other message signed with the sequencer's key.
+var signingDomain = []byte("TAIKO_PRECONF_BLOCK")
+
+// signingHash returns the hash signed by the sequencer for the given block of the
+// given chain, keccak256(domain || chainID || blockHash), so the envelopes of one
+// network are never accepted on another network sharing the sequencer's key.
+func signingHash(chainID *big.Int, blockHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash(signingDomain, common.BigToHash(chainID).Bytes(), blockHash.Bytes())
+}
+
+// PreconfBlockPacket is a preconfirmation L2 block envelope, signed by the
+// sequencer which sealed the block.
+type PreconfBlockPacket struct {
+	Block     *types.Block // Preconfirmation block, not proposed on L1 yet
+	Signature []byte       // Sequencer's [R || S || V] signature of the signing hash
+}
+
+// NewPreconfBlockPacket signs the given preconfirmation block of the given chain
+// with the sequencer's private key, and wraps it into an envelope.
+func NewPreconfBlockPacket(block *types.Block, chainID *big.Int, key *ecdsa.PrivateKey) (*PreconfBlockPacket, error) {
+	hash := signingHash(chainID, block.Hash())
+	sig, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		return nil, err
+	}
+	return &PreconfBlockPacket{Block: block, Signature: sig}, nil
+}
+
+// Signer recovers the address of the sequencer which signed the envelope for the
+// given chain.
+func (p *PreconfBlockPacket) Signer(chainID *big.Int) (common.Address, error) {
+	if p.Block == nil {
+		return common.Address{}, errInvalidBlock
+	}
+	if len(p.Signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: signature length %d", errInvalidBlock, len(p.Signature))
+	}
+	hash := signingHash(chainID, p.Block.Hash())
+	pubkey, err := crypto.SigToPub(hash[:], p.Signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", errInvalidBlock, err)
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+func (*PreconfBlockPacket) Name() string { return "PreconfBlock" }
+func (*PreconfBlockPacket) Kind() byte   { return PreconfBlockMsg }
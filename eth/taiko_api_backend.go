@@ -1,14 +1,27 @@
 package eth
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // TaikoAPIBackend handles L2 node related RPC calls.
@@ -23,13 +36,35 @@ func NewTaikoAPIBackend(eth *Ethereum) *TaikoAPIBackend {
 	}
 }
 
-// HeadL1Origin returns the latest L2 block's corresponding L1 origin.
+// HeadL1Origin returns the latest L2 block's corresponding L1 origin, preconfirmation
+// blocks which have not been proposed on L1 yet are not included.
 func (s *TaikoAPIBackend) HeadL1Origin() (*rawdb.L1Origin, error) {
+	return s.L1ConfirmedTip()
+}
+
+// L1ConfirmedTip returns the L1 origin of the highest L2 block proposed on L1.
+func (s *TaikoAPIBackend) L1ConfirmedTip() (*rawdb.L1Origin, error) {
 	blockID, err := rawdb.ReadHeadL1Origin(s.eth.ChainDb())
 	if err != nil {
 		return nil, err
 	}
 
+	return s.l1OriginByMarker(blockID)
+}
+
+// PreconfTip returns the L1 origin of the highest preconfirmation L2 block, which has
+// not been proposed on L1 yet.
+func (s *TaikoAPIBackend) PreconfTip() (*rawdb.L1Origin, error) {
+	blockID, err := rawdb.ReadHeadPreconfL1Origin(s.eth.ChainDb())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.l1OriginByMarker(blockID)
+}
+
+// l1OriginByMarker returns the L1 origin of the L2 block referenced by a head marker.
+func (s *TaikoAPIBackend) l1OriginByMarker(blockID *big.Int) (*rawdb.L1Origin, error) {
 	if blockID == nil {
 		return nil, ethereum.NotFound
 	}
@@ -60,6 +95,245 @@ func (s *TaikoAPIBackend) L1OriginByID(blockID *math.HexOrDecimal256) (*rawdb.L1
 	return l1Origin, nil
 }
 
+// L1OriginsByL1BlockHash returns the L1 origins of all L2 blocks proposed in the given L1 block.
+func (s *TaikoAPIBackend) L1OriginsByL1BlockHash(l1BlockHash common.Hash) ([]*rawdb.L1Origin, error) {
+	return rawdb.ReadL1OriginsByL1BlockHash(s.eth.ChainDb(), l1BlockHash)
+}
+
+// L1OriginsByL1Height returns the L1 origins of all L2 blocks proposed at the given L1 block height.
+func (s *TaikoAPIBackend) L1OriginsByL1Height(l1BlockHeight *math.HexOrDecimal256) ([]*rawdb.L1Origin, error) {
+	return rawdb.ReadL1OriginsByL1Height(s.eth.ChainDb(), (*big.Int)(l1BlockHeight))
+}
+
+// SkippedTransactions returns the proposed transactions which were left out of the
+// canonical L2 block with the given ID, together with the reasons.
+func (s *TaikoAPIBackend) SkippedTransactions(blockID *math.HexOrDecimal256) ([]*rawdb.SkippedTransaction, error) {
+	if blockID == nil || !(*big.Int)(blockID).IsUint64() {
+		return nil, errors.New("invalid block ID")
+	}
+
+	blockHash := rawdb.ReadCanonicalHash(s.eth.ChainDb(), (*big.Int)(blockID).Uint64())
+	if blockHash == (common.Hash{}) {
+		return nil, ethereum.NotFound
+	}
+
+	txs, err := rawdb.ReadSkippedTransactions(s.eth.ChainDb(), blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if txs == nil {
+		return []*rawdb.SkippedTransaction{}, nil
+	}
+
+	return txs, nil
+}
+
+// AnchorInfo returns the decoded arguments of the anchor transaction of the given
+// L2 block, which tell the L1 block the L2 block is anchored to.
+func (s *TaikoAPIBackend) AnchorInfo(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*taiko.AnchorInfo, error) {
+	engine, ok := s.eth.Engine().(*taiko.Taiko)
+	if !ok {
+		return nil, errors.New("taiko consensus engine not used")
+	}
+
+	block, err := s.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+
+	if len(block.Transactions()) == 0 {
+		return nil, taiko.ErrAnchorTxNotFound
+	}
+
+	return engine.DecodeAnchorTx(block.Transactions()[0])
+}
+
+// GetSignalProof returns the Merkle proof of the signal sent by the given app through
+// the SignalService contract at the given L2 block, together with the L1 origin and
+// the anchor of the block.
+func (s *TaikoAPIBackend) GetSignalProof(
+	ctx context.Context,
+	signalService common.Address,
+	app common.Address,
+	signal common.Hash,
+	blockNrOrHash rpc.BlockNumberOrHash,
+) (*taiko.SignalProof, error) {
+	header, err := s.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if header == nil {
+		return nil, ethereum.NotFound
+	}
+
+	l1Origin, err := rawdb.ReadL1Origin(s.eth.ChainDb(), header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	if l1Origin == nil || l1Origin.L2BlockHash != header.Hash() {
+		return nil, fmt.Errorf("L1 origin of block %d not found", header.Number)
+	}
+
+	// Pin the block by hash, so the proof, the L1 origin and the anchor all belong
+	// to the same block.
+	blockHash := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
+	anchor, err := s.AnchorInfo(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	slot := taiko.SignalSlot(s.eth.BlockChain().Config().ChainID.Uint64(), app, signal)
+	res, err := ethapi.NewBlockChainAPI(s.eth.APIBackend).GetProof(ctx, signalService, []string{slot.Hex()}, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &taiko.AccountProof{
+		Address:      res.Address,
+		AccountProof: res.AccountProof,
+		Balance:      res.Balance,
+		CodeHash:     res.CodeHash,
+		Nonce:        res.Nonce,
+		StorageHash:  res.StorageHash,
+	}
+	for _, storage := range res.StorageProof {
+		proof.StorageProof = append(proof.StorageProof, taiko.StorageProof{
+			Key:   storage.Key,
+			Value: storage.Value,
+			Proof: storage.Proof,
+		})
+	}
+
+	return &taiko.SignalProof{
+		Slot:        slot,
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		StateRoot:   header.Root,
+		L1Origin:    l1Origin,
+		Anchor:      anchor,
+		Proof:       proof,
+	}, nil
+}
+
+// ExecutionWitness returns the RLP encoded witness of the given L2 block, which holds
+// the state trie nodes, codes and headers needed to execute the block statelessly with
+// core.ExecuteStateless.
+func (s *TaikoAPIBackend) ExecutionWitness(ctx context.Context, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	block, err := s.eth.APIBackend.BlockByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis block has no witness")
+	}
+
+	witness, err := generateWitness(s.eth.BlockChain(), block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate witness of block %d: %w", block.NumberU64(), err)
+	}
+
+	return rlp.EncodeToBytes(witness)
+}
+
+// generateWitness re-executes the given block on top of the state of its parent, and
+// collects the witness of the accessed state.
+func generateWitness(chain *core.BlockChain, block *types.Block) (*stateless.Witness, error) {
+	witness, err := stateless.NewWitness(block.Header(), chain)
+	if err != nil {
+		return nil, err
+	}
+
+	statedb, err := chain.StateAt(witness.Headers[0].Root)
+	if err != nil {
+		return nil, err
+	}
+
+	statedb.StartPrefetcher("taiko", witness)
+	defer statedb.StopPrefetcher()
+
+	// Don't replay the block into the live tracer of the chain, it is only executed
+	// again to collect the witness.
+	res, err := chain.Processor().Process(block, statedb, vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validating the state also computes the post state root, which pulls the
+	// modified trie nodes into the witness.
+	if err := chain.Validator().ValidateState(block, statedb, res, false); err != nil {
+		return nil, err
+	}
+
+	return witness, nil
+}
+
+// maxFeeBreakdownBlocks is the maximum number of blocks taiko_feeBreakdown covers.
+const maxFeeBreakdownBlocks = 1024
+
+// FeeBreakdown returns the fee revenue of the L2 blocks in the given range, in total
+// and per block: the base fees paid to the treasury and shared with the coinbase, the
+// priority fees, and the anchor transactions which pay no fees.
+func (s *TaikoAPIBackend) FeeBreakdown(ctx context.Context, fromBlock, toBlock rpc.BlockNumber) (*types.FeeBreakdownRange, error) {
+	from, err := s.eth.APIBackend.HeaderByNumber(ctx, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.eth.APIBackend.HeaderByNumber(ctx, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil || to == nil {
+		return nil, ethereum.NotFound
+	}
+	if from.Number.Cmp(to.Number) > 0 {
+		return nil, fmt.Errorf("invalid block range: from %v is after to %v", from.Number, to.Number)
+	}
+	if blocks := to.Number.Uint64() - from.Number.Uint64() + 1; blocks > maxFeeBreakdownBlocks {
+		return nil, fmt.Errorf("block range too large: %d blocks, max %d", blocks, maxFeeBreakdownBlocks)
+	}
+
+	result := &types.FeeBreakdownRange{
+		FromBlock: hexutil.Uint64(from.Number.Uint64()),
+		ToBlock:   hexutil.Uint64(to.Number.Uint64()),
+		Total:     types.NewFeeBreakdown(),
+	}
+	for number := from.Number.Uint64(); number <= to.Number.Uint64(); number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := s.eth.APIBackend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		receipts, err := s.eth.APIBackend.GetReceipts(ctx, block.Hash())
+		if err != nil {
+			return nil, err
+		}
+		fees, err := core.TaikoFeeBreakdown(s.eth.BlockChain().Config(), block, receipts)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", number, err)
+		}
+		result.Total.Add(fees.FeeBreakdown)
+		result.Blocks = append(result.Blocks, fees)
+	}
+	return result, nil
+}
+
 // GetSyncMode returns the node sync mode.
 func (s *TaikoAPIBackend) GetSyncMode() (string, error) {
 	return s.eth.config.SyncMode.String(), nil
@@ -75,7 +349,112 @@ func NewTaikoAuthAPIBackend(eth *Ethereum) *TaikoAuthAPIBackend {
 	return &TaikoAuthAPIBackend{eth}
 }
 
-// TxPoolContent retrieves the transaction pool content with the given upper limits.
+// RewindToL1Height rewinds the L2 chain to the last block whose L1 origin is at or below
+// the given L1 block height, and returns the new head L1 origin.
+func (a *TaikoAuthAPIBackend) RewindToL1Height(l1Height *math.HexOrDecimal256) (*rawdb.L1Origin, error) {
+	a.eth.handler.downloader.Cancel()
+
+	return a.eth.BlockChain().RewindToL1Height((*big.Int)(l1Height))
+}
+
+// UpdateL1Origin confirms an inserted preconfirmation L2 block by attaching the
+// L1 origin of its L1 proposal, and returns the stored L1 origin.
+func (a *TaikoAuthAPIBackend) UpdateL1Origin(l1Origin *rawdb.L1Origin) (*rawdb.L1Origin, error) {
+	return a.eth.BlockChain().ConfirmL1Origin(l1Origin)
+}
+
+// BuildAnchorTx builds the TaikoL2.anchorV2 transaction of the L2 block with the given
+// parent and timestamp, signed by the golden touch account, so that the drivers don't
+// need to encode and sign it by themselves.
+func (a *TaikoAuthAPIBackend) BuildAnchorTx(
+	parentHash common.Hash,
+	timestamp uint64,
+	l1Height uint64,
+	l1StateRoot common.Hash,
+	parentGasUsed uint32,
+	baseFeeConfig *taiko.BaseFeeConfig,
+	baseFee *big.Int,
+) (*types.Transaction, error) {
+	engine, ok := a.eth.Engine().(*taiko.Taiko)
+	if !ok {
+		return nil, errors.New("taiko consensus engine not used")
+	}
+	if baseFeeConfig == nil || baseFee == nil {
+		return nil, errors.New("missing base fee config or base fee")
+	}
+
+	parent := a.eth.BlockChain().GetHeaderByHash(parentHash)
+	if parent == nil {
+		return nil, fmt.Errorf("unknown parent block %s", parentHash)
+	}
+	if timestamp < parent.Time {
+		return nil, fmt.Errorf("timestamp %d older than parent %d", timestamp, parent.Time)
+	}
+	state, err := a.eth.BlockChain().StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	header := &types.Header{
+		ParentHash: parentHash,
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Time:       timestamp,
+		BaseFee:    baseFee,
+	}
+
+	return engine.NewAnchorV2Tx(
+		header,
+		state.GetNonce(a.eth.BlockChain().Config().GoldenTouchAccount()),
+		l1Height,
+		l1StateRoot,
+		parentGasUsed,
+		baseFeeConfig,
+	)
+}
+
+// SendBundleArgs represents the arguments of a bundle sent by a searcher, the
+// transactions are included all together or not at all.
+type SendBundleArgs struct {
+	Txs []hexutil.Bytes `json:"txs"`
+
+	// Range of the L2 block numbers the bundle targets, zero means unbounded.
+	MinBlockNumber hexutil.Uint64 `json:"minBlockNumber,omitempty"`
+	MaxBlockNumber hexutil.Uint64 `json:"maxBlockNumber,omitempty"`
+
+	// Transactions which are allowed to revert without dropping the bundle.
+	RevertingTxHashes []common.Hash `json:"revertingTxHashes,omitempty"`
+}
+
+// SendBundle adds a bundle of signed transactions into the bundle pool, which are
+// simulated as a unit when building the transactions lists. It returns the hash of
+// the bundle.
+func (a *TaikoAuthAPIBackend) SendBundle(args SendBundleArgs) (common.Hash, error) {
+	txs := make(types.Transactions, len(args.Txs))
+	for i, input := range args.Txs {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(input); err != nil {
+			return common.Hash{}, fmt.Errorf("invalid transaction %d: %w", i, err)
+		}
+	}
+
+	pool := a.eth.BundlePool()
+	if pool == nil {
+		return common.Hash{}, errors.New("bundle pool not enabled")
+	}
+
+	return pool.Add(&bundlepool.Bundle{
+		Txs:               txs,
+		MinBlock:          uint64(args.MinBlockNumber),
+		MaxBlock:          uint64(args.MaxBlockNumber),
+		RevertingTxHashes: args.RevertingTxHashes,
+	})
+}
+
+// TxPoolContent retrieves the transaction pool content with the given upper limits,
+// the optional blob budget limits each transactions list by the blobs it will be
+// posted in, and the optional ordering strategy picks the order of the transactions
+// by its name, followed by its parameter if any, e.g. "senderCap:8" caps each sender
+// at 8 transactions per list, 16 by default. The fees are estimated with the optional extradata of the proposed
+// block, which carries its base fee sharing percentage.
 func (a *TaikoAuthAPIBackend) TxPoolContent(
 	beneficiary common.Address,
 	baseFee *big.Int,
@@ -83,7 +462,16 @@ func (a *TaikoAuthAPIBackend) TxPoolContent(
 	maxBytesPerTxList uint64,
 	locals []string,
 	maxTransactionsLists uint64,
+	blobBudget *miner.BlobBudget,
+	orderingStrategy *string,
+	extraData *hexutil.Bytes,
 ) ([]*miner.PreBuiltTxList, error) {
+	var strategy string
+	if orderingStrategy != nil {
+		strategy = *orderingStrategy
+	}
+	extra := a.proposalExtraData(extraData)
+
 	log.Debug(
 		"Fetching L2 pending transactions finished",
 		"baseFee", baseFee,
@@ -91,19 +479,29 @@ func (a *TaikoAuthAPIBackend) TxPoolContent(
 		"maxBytesPerTxList", maxBytesPerTxList,
 		"maxTransactions", maxTransactionsLists,
 		"locals", locals,
+		"blobBudget", blobBudget,
+		"orderingStrategy", strategy,
+		"extraData", hexutil.Bytes(extra),
 	)
 
 	return a.eth.Miner().BuildTransactionsLists(
 		beneficiary,
 		baseFee,
+		extra,
 		blockMaxGasLimit,
 		maxBytesPerTxList,
 		locals,
 		maxTransactionsLists,
+		blobBudget,
+		strategy,
 	)
 }
 
-// TxPoolContentWithMinTip retrieves the transaction pool content with the given upper limits and minimum tip.
+// TxPoolContentWithMinTip retrieves the transaction pool content with the given upper limits and minimum tip,
+// the optional blob budget limits each transactions list by the blobs it will be posted in, and the
+// optional ordering strategy picks the order of the transactions by its name, followed by its parameter
+// if any, e.g. "senderCap:8" caps each sender at 8 transactions per list, 16 by default. The fees are estimated
+// with the optional extradata of the proposed block, which carries its base fee sharing percentage.
 func (a *TaikoAuthAPIBackend) TxPoolContentWithMinTip(
 	beneficiary common.Address,
 	baseFee *big.Int,
@@ -112,7 +510,16 @@ func (a *TaikoAuthAPIBackend) TxPoolContentWithMinTip(
 	locals []string,
 	maxTransactionsLists uint64,
 	minTip uint64,
+	blobBudget *miner.BlobBudget,
+	orderingStrategy *string,
+	extraData *hexutil.Bytes,
 ) ([]*miner.PreBuiltTxList, error) {
+	var strategy string
+	if orderingStrategy != nil {
+		strategy = *orderingStrategy
+	}
+	extra := a.proposalExtraData(extraData)
+
 	log.Debug(
 		"Fetching L2 pending transactions finished",
 		"baseFee", baseFee,
@@ -121,15 +528,31 @@ func (a *TaikoAuthAPIBackend) TxPoolContentWithMinTip(
 		"maxTransactions", maxTransactionsLists,
 		"locals", locals,
 		"minTip", minTip,
+		"blobBudget", blobBudget,
+		"orderingStrategy", strategy,
+		"extraData", hexutil.Bytes(extra),
 	)
 
 	return a.eth.Miner().BuildTransactionsListsWithMinTip(
 		beneficiary,
 		baseFee,
+		extra,
 		blockMaxGasLimit,
 		maxBytesPerTxList,
 		locals,
 		maxTransactionsLists,
 		minTip,
+		blobBudget,
+		strategy,
 	)
 }
+
+// proposalExtraData returns the given extradata of the proposed block, or the one of
+// the current head block if it's not given, since the base fee sharing percentage
+// rarely changes between the proposed blocks.
+func (a *TaikoAuthAPIBackend) proposalExtraData(extraData *hexutil.Bytes) []byte {
+	if extraData != nil {
+		return *extraData
+	}
+	return a.eth.BlockChain().CurrentBlock().Extra
+}
@@ -17,12 +17,16 @@
 package simulated
 
 import (
+	"context"
 	"errors"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/catalyst"
@@ -52,6 +56,13 @@ type Client interface {
 	ethereum.TransactionReader
 	ethereum.TransactionSender
 	ethereum.ChainIDReader
+
+	// CHANGE(taiko): the L1Origins of the blocks, the signal proofs and the execution
+	// witnesses, only available on the backends created with the WithTaiko option.
+	HeadL1Origin(ctx context.Context) (*rawdb.L1Origin, error)
+	L1OriginByID(ctx context.Context, blockID *big.Int) (*rawdb.L1Origin, error)
+	GetSignalProof(ctx context.Context, signalService common.Address, app common.Address, signal common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*taiko.SignalProof, error)
+	ExecutionWitness(ctx context.Context, number *big.Int) ([]byte, error)
 }
 
 // simClient wraps ethclient. This exists to prevent extracting ethclient.Client
@@ -116,12 +127,24 @@ func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backe
 		Namespace: "eth",
 		Service:   filters.NewFilterAPI(filterSystem),
 	}})
+	// CHANGE(taiko): register the Taiko APIs, which can't be added once the node
+	// is started.
+	isTaiko := backend.BlockChain().Config().Taiko
+	if isTaiko {
+		registerTaikoAPIs(stack, backend)
+	}
 	// Start the node
 	if err := stack.Start(); err != nil {
 		return nil, err
 	}
 	// Set up the simulated beacon
-	beacon, err := catalyst.NewSimulatedBeacon(blockPeriod, backend)
+	var beacon *catalyst.SimulatedBeacon
+	// CHANGE(taiko): seal the blocks the Taiko way on a Taiko chain.
+	if isTaiko {
+		beacon, err = catalyst.NewTaikoSimulatedBeacon(blockPeriod, backend, catalyst.DefaultTaikoDevConfig())
+	} else {
+		beacon, err = catalyst.NewSimulatedBeacon(blockPeriod, backend)
+	}
 	if err != nil {
 		return nil, err
 	}
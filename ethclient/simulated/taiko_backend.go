@@ -0,0 +1,26 @@
+package simulated
+
+import (
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// WithTaiko configures the simulated backend to run the Taiko developer chain:
+// the blocks are sealed the way the driver does, with an anchor transaction and
+// a L1Origin, and the base fee is sent to the treasury instead of being burnt.
+func WithTaiko() func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+	return func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		ethConf.Genesis.Config = params.TaikoDevChainConfig()
+	}
+}
+
+// registerTaikoAPIs registers the public Taiko RPC APIs of the backend.
+func registerTaikoAPIs(stack *node.Node, backend *eth.Ethereum) {
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "taiko",
+		Service:   eth.NewTaikoAPIBackend(backend),
+	}})
+}
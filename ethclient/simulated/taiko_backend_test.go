@@ -0,0 +1,241 @@
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+func TestTaikoBackend(t *testing.T) {
+	alloc := types.GenesisAlloc{testAddr: {Balance: big.NewInt(10000000000000000)}}
+	sim := NewBackend(alloc, WithTaiko())
+	defer sim.Close()
+
+	var (
+		ctx    = context.Background()
+		client = sim.Client()
+		config = params.TaikoDevChainConfig()
+	)
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	block, err := client.BlockByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := block.Transactions()
+	if block.NumberU64() != 1 || len(txs) != 2 {
+		t.Fatalf("unexpected block %d with %d transactions", block.NumberU64(), len(txs))
+	}
+	// The anchor transaction comes first, sent by the golden touch account.
+	if sender, err := types.Sender(types.LatestSigner(config), txs[0]); err != nil || sender != config.GoldenTouchAccount() {
+		t.Fatalf("unexpected anchor sender: %v, %v", sender, err)
+	}
+	if txs[1].Hash() != tx.Hash() {
+		t.Fatalf("unexpected transaction: have %v, want %v", txs[1].Hash(), tx.Hash())
+	}
+
+	// The base fee is shared between the treasury and the coinbase, instead of
+	// being burnt.
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	treasuryIncome, _ := core.SplitTaikoBaseFee(block.BaseFee(), receipt.GasUsed, 75)
+	if balance, err := client.BalanceAt(ctx, config.TaikoTreasuryAddress(), nil); err != nil || balance.Cmp(treasuryIncome) != 0 {
+		t.Fatalf("unexpected treasury balance: have %v, want %v (err %v)", balance, treasuryIncome, err)
+	}
+
+	// The L1Origin of the block is available through the client.
+	head, err := client.HeadL1Origin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.BlockID.Uint64() != 1 || head.L2BlockHash != block.Hash() {
+		t.Fatalf("unexpected head L1Origin: %+v", head)
+	}
+	l1Origin, err := client.L1OriginByID(ctx, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l1Origin.L2BlockHash != block.Hash() || l1Origin.IsPreconfBlock {
+		t.Fatalf("unexpected L1Origin: %+v", l1Origin)
+	}
+
+	// The blocks pass the Taiko consensus verification when imported by another node.
+	sim.Commit()
+	genesis := &core.Genesis{Config: config, GasLimit: ethconfig.Defaults.Miner.GasCeil, Alloc: alloc}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, taiko.New(config), vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	var blocks types.Blocks
+	for i := int64(1); i <= 2; i++ {
+		block, err := client.BlockByNumber(ctx, big.NewInt(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, block)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import the blocks: %v", err)
+	}
+}
+
+func TestTaikoBackendSignalProof(t *testing.T) {
+	var (
+		config        = params.TaikoDevChainConfig()
+		signalService = common.HexToAddress("0x1337000000000000000000000000000000000005")
+		app           = common.HexToAddress("0x1337000000000000000000000000000000000001")
+		signal        = common.HexToHash("0xc0ffee")
+		slot          = taiko.SignalSlot(config.ChainID.Uint64(), app, signal)
+	)
+	alloc := types.GenesisAlloc{
+		testAddr:      {Balance: big.NewInt(10000000000000000)},
+		signalService: {Code: []byte{0x00}, Storage: map[common.Hash]common.Hash{slot: signal}},
+	}
+	sim := NewBackend(alloc, WithTaiko())
+	defer sim.Close()
+	sim.Commit()
+
+	var (
+		ctx    = context.Background()
+		client = sim.Client()
+	)
+	block, err := client.BlockByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := client.GetSignalProof(ctx, signalService, app, signal, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.Slot != slot || proof.BlockHash != block.Hash() || proof.StateRoot != block.Root() || uint64(proof.BlockNumber) != 1 {
+		t.Fatalf("unexpected signal proof: %+v", proof)
+	}
+	if proof.L1Origin == nil || proof.L1Origin.L2BlockHash != block.Hash() {
+		t.Fatalf("unexpected L1Origin: %+v", proof.L1Origin)
+	}
+	if proof.Anchor == nil || proof.Anchor.L1Height != 1 {
+		t.Fatalf("unexpected anchor: %+v", proof.Anchor)
+	}
+
+	// The account proof resolves to the storage root of the signal service, and the
+	// storage proof of the signal slot resolves to the signal.
+	account, err := trie.VerifyProof(block.Root(), crypto.Keccak256(signalService.Bytes()), proofDB(t, proof.Proof.AccountProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state types.StateAccount
+	if err := rlp.DecodeBytes(account, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Root != proof.Proof.StorageHash || len(proof.Proof.StorageProof) != 1 {
+		t.Fatalf("unexpected account proof: %+v", proof.Proof)
+	}
+	value, err := trie.VerifyProof(state.Root, crypto.Keccak256(slot.Bytes()), proofDB(t, proof.Proof.StorageProof[0].Proof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored []byte
+	if err := rlp.DecodeBytes(value, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToHash(stored) != signal || proof.Proof.StorageProof[0].Value.ToInt().Cmp(signal.Big()) != 0 {
+		t.Fatalf("unexpected signal slot value: %x", stored)
+	}
+
+	// The genesis block has no L1Origin.
+	if _, err := client.GetSignalProof(ctx, signalService, app, signal, rpc.BlockNumberOrHashWithNumber(0)); err == nil {
+		t.Fatal("expected an error for a block without L1Origin")
+	}
+}
+
+// proofDB returns a database holding the given hex encoded proof nodes.
+func proofDB(t *testing.T, proof []string) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range proof {
+		blob, err := hexutil.Decode(node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.Put(crypto.Keccak256(blob), blob)
+	}
+	return db
+}
+
+func TestTaikoBackendExecutionWitness(t *testing.T) {
+	alloc := types.GenesisAlloc{testAddr: {Balance: big.NewInt(10000000000000000)}}
+	sim := NewBackend(alloc, WithTaiko())
+	defer sim.Close()
+
+	var (
+		ctx    = context.Background()
+		client = sim.Client()
+		config = params.TaikoDevChainConfig()
+	)
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	block, err := client.BlockByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, err := client.ExecutionWitness(ctx, block.Number())
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness := new(stateless.Witness)
+	if err := rlp.DecodeBytes(blob, witness); err != nil {
+		t.Fatal(err)
+	}
+
+	// Executing the block on top of the witness alone reproduces the roots of the
+	// header, with the anchor transaction and the base fee sharing applied.
+	header := block.Header()
+	header.Root, header.ReceiptHash = common.Hash{}, common.Hash{}
+	stateRoot, receiptRoot, err := core.ExecuteStateless(config, block.WithSeal(header), witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stateRoot != block.Root() {
+		t.Fatalf("state root mismatch: have %v, want %v", stateRoot, block.Root())
+	}
+	if receiptRoot != block.ReceiptHash() {
+		t.Fatalf("receipt root mismatch: have %v, want %v", receiptRoot, block.ReceiptHash())
+	}
+
+	if _, err := client.ExecutionWitness(ctx, common.Big0); err == nil {
+		t.Fatal("expected an error for the genesis block")
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // HeadL1Origin returns the latest L2 block's corresponding L1 origin.
@@ -19,6 +23,28 @@ func (ec *Client) HeadL1Origin(ctx context.Context) (*rawdb.L1Origin, error) {
 	return res, nil
 }
 
+// L1ConfirmedTip returns the L1 origin of the highest L2 block proposed on L1.
+func (ec *Client) L1ConfirmedTip(ctx context.Context) (*rawdb.L1Origin, error) {
+	var res *rawdb.L1Origin
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_l1ConfirmedTip"); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PreconfTip returns the L1 origin of the highest preconfirmation L2 block.
+func (ec *Client) PreconfTip(ctx context.Context) (*rawdb.L1Origin, error) {
+	var res *rawdb.L1Origin
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_preconfTip"); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // L1OriginByID returns the L2 block's corresponding L1 origin.
 func (ec *Client) L1OriginByID(ctx context.Context, blockID *big.Int) (*rawdb.L1Origin, error) {
 	var res *rawdb.L1Origin
@@ -30,6 +56,95 @@ func (ec *Client) L1OriginByID(ctx context.Context, blockID *big.Int) (*rawdb.L1
 	return res, nil
 }
 
+// L1OriginsByL1BlockHash returns the L1 origins of all L2 blocks proposed in the given L1 block.
+func (ec *Client) L1OriginsByL1BlockHash(ctx context.Context, l1BlockHash common.Hash) ([]*rawdb.L1Origin, error) {
+	var res []*rawdb.L1Origin
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_l1OriginsByL1BlockHash", l1BlockHash); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// L1OriginsByL1Height returns the L1 origins of all L2 blocks proposed at the given L1 block height.
+func (ec *Client) L1OriginsByL1Height(ctx context.Context, l1BlockHeight *big.Int) ([]*rawdb.L1Origin, error) {
+	var res []*rawdb.L1Origin
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_l1OriginsByL1Height", hexutil.EncodeBig(l1BlockHeight)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SkippedTransactions returns the proposed transactions which were left out of the
+// canonical L2 block with the given ID, together with the reasons.
+func (ec *Client) SkippedTransactions(ctx context.Context, blockID *big.Int) ([]*rawdb.SkippedTransaction, error) {
+	var res []*rawdb.SkippedTransaction
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_skippedTransactions", hexutil.EncodeBig(blockID)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AnchorInfo returns the decoded arguments of the anchor transaction of the given
+// L2 block, which tell the L1 block the L2 block is anchored to.
+func (ec *Client) AnchorInfo(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*taiko.AnchorInfo, error) {
+	var res *taiko.AnchorInfo
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_anchorInfo", blockNrOrHash); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FeeBreakdown returns the fee revenue of the L2 blocks in the given range, in total
+// and per block.
+func (ec *Client) FeeBreakdown(ctx context.Context, fromBlock, toBlock *big.Int) (*types.FeeBreakdownRange, error) {
+	var res *types.FeeBreakdownRange
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_feeBreakdown", toBlockNumArg(fromBlock), toBlockNumArg(toBlock)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetSignalProof returns the Merkle proof of the signal sent by the given app through
+// the SignalService contract at the given L2 block, together with the L1 origin and
+// the anchor of the block.
+func (ec *Client) GetSignalProof(
+	ctx context.Context,
+	signalService common.Address,
+	app common.Address,
+	signal common.Hash,
+	blockNrOrHash rpc.BlockNumberOrHash,
+) (*taiko.SignalProof, error) {
+	var res *taiko.SignalProof
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_getSignalProof", signalService, app, signal, blockNrOrHash); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ExecutionWitness returns the RLP encoded witness of the given L2 block, which can
+// be decoded into a stateless.Witness to execute the block with core.ExecuteStateless.
+func (ec *Client) ExecutionWitness(ctx context.Context, number *big.Int) ([]byte, error) {
+	var res hexutil.Bytes
+
+	if err := ec.c.CallContext(ctx, &res, "taiko_executionWitness", toBlockNumArg(number)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // GetSyncMode returns the current sync mode of the L2 node.
 func (ec *Client) GetSyncMode(ctx context.Context) (string, error) {
 	var res string
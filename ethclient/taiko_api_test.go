@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
@@ -104,6 +105,114 @@ func TestL1OriginByID(t *testing.T) {
 	require.Equal(t, testL1Origin, l1OriginFound)
 }
 
+func TestL1OriginsByL1Block(t *testing.T) {
+	ec, blocks, db := newTaikoAPITestClient(t)
+
+	testL1Origin := &rawdb.L1Origin{
+		BlockID:       randomBigInt(),
+		L2BlockHash:   blocks[len(blocks)-1].Hash(),
+		L1BlockHeight: randomBigInt(),
+		L1BlockHash:   randomHash(),
+	}
+
+	l1OriginsFound, err := ec.L1OriginsByL1BlockHash(context.Background(), testL1Origin.L1BlockHash)
+	require.Nil(t, err)
+	require.Empty(t, l1OriginsFound)
+
+	l1OriginsFound, err = ec.L1OriginsByL1Height(context.Background(), testL1Origin.L1BlockHeight)
+	require.Nil(t, err)
+	require.Empty(t, l1OriginsFound)
+
+	rawdb.WriteL1Origin(db, testL1Origin.BlockID, testL1Origin)
+	rawdb.UpdateL1OriginIndexes(db, db, nil, []*rawdb.L1Origin{testL1Origin})
+
+	l1OriginsFound, err = ec.L1OriginsByL1BlockHash(context.Background(), testL1Origin.L1BlockHash)
+	require.Nil(t, err)
+	require.Equal(t, []*rawdb.L1Origin{testL1Origin}, l1OriginsFound)
+
+	l1OriginsFound, err = ec.L1OriginsByL1Height(context.Background(), testL1Origin.L1BlockHeight)
+	require.Nil(t, err)
+	require.Equal(t, []*rawdb.L1Origin{testL1Origin}, l1OriginsFound)
+}
+
+func TestPreconfTip(t *testing.T) {
+	ec, blocks, db := newTaikoAPITestClient(t)
+
+	l1OriginFound, err := ec.PreconfTip(context.Background())
+	require.Equal(t, ethereum.NotFound.Error(), err.Error())
+	require.Nil(t, l1OriginFound)
+
+	confirmedL1Origin := &rawdb.L1Origin{
+		BlockID:       big.NewInt(1),
+		L2BlockHash:   blocks[1].Hash(),
+		L1BlockHeight: randomBigInt(),
+		L1BlockHash:   randomHash(),
+	}
+	preconfL1Origin := &rawdb.L1Origin{
+		BlockID:        big.NewInt(2),
+		L2BlockHash:    blocks[2].Hash(),
+		IsPreconfBlock: true,
+	}
+	rawdb.WriteL1Origin(db, confirmedL1Origin.BlockID, confirmedL1Origin)
+	rawdb.WriteHeadL1Origin(db, confirmedL1Origin.BlockID)
+	rawdb.WriteL1Origin(db, preconfL1Origin.BlockID, preconfL1Origin)
+	rawdb.WriteHeadPreconfL1Origin(db, preconfL1Origin.BlockID)
+
+	l1OriginFound, err = ec.PreconfTip(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, preconfL1Origin, l1OriginFound)
+
+	l1OriginFound, err = ec.L1ConfirmedTip(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, confirmedL1Origin, l1OriginFound)
+
+	l1OriginFound, err = ec.HeadL1Origin(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, confirmedL1Origin, l1OriginFound)
+}
+
+func TestSkippedTransactions(t *testing.T) {
+	ec, blocks, db := newTaikoAPITestClient(t)
+
+	txs, err := ec.SkippedTransactions(context.Background(), big.NewInt(int64(len(blocks))))
+	require.Equal(t, ethereum.NotFound.Error(), err.Error())
+	require.Nil(t, txs)
+
+	txs, err = ec.SkippedTransactions(context.Background(), common.Big1)
+	require.Nil(t, err)
+	require.Empty(t, txs)
+
+	skipped := []*rawdb.SkippedTransaction{{Hash: randomHash(), Reason: "nonce too high"}}
+	rawdb.WriteSkippedTransactions(db, blocks[1].Hash(), skipped)
+
+	txs, err = ec.SkippedTransactions(context.Background(), common.Big1)
+	require.Nil(t, err)
+	require.Equal(t, skipped, txs)
+
+	// A missing block ID is rejected.
+	err = ec.Client().CallContext(context.Background(), &txs, "taiko_skippedTransactions", nil)
+	require.ErrorContains(t, err, "invalid block ID")
+}
+
+func TestFeeBreakdown(t *testing.T) {
+	ec, blocks, _ := newTaikoAPITestClient(t)
+
+	fees, err := ec.FeeBreakdown(context.Background(), common.Big0, nil)
+	require.Nil(t, err)
+	require.Equal(t, hexutil.Uint64(0), fees.FromBlock)
+	require.Equal(t, hexutil.Uint64(len(blocks)-1), fees.ToBlock)
+	require.Len(t, fees.Blocks, len(blocks))
+	for i, block := range fees.Blocks {
+		require.Equal(t, blocks[i].Hash(), block.Hash)
+	}
+	// The test chain is not a Taiko chain, so the base fees are burnt.
+	require.Zero(t, fees.Total.TreasuryIncome.ToInt().Sign())
+	require.Zero(t, fees.Total.AnchorTxs)
+
+	_, err = ec.FeeBreakdown(context.Background(), common.Big1, common.Big0)
+	require.Error(t, err)
+}
+
 // randomHash generates a random blob of data and returns it as a hash.
 func randomHash() common.Hash {
 	var hash common.Hash
@@ -149,6 +149,12 @@ func TestGraphQLBlockSerialization(t *testing.T) {
 			want: `{"data":{"block":{"number":"0xa","call":{"data":"0x","status":"0x1"}}}}`,
 			code: 200,
 		},
+		// CHANGE(taiko): should return null anchor info on a non-Taiko chain
+		{
+			body: `{"query": "{block{anchorInfo{l1Height}}}"}`,
+			want: `{"data":{"block":{"anchorInfo":null}}}`,
+			code: 200,
+		},
 		{
 			body: `{"query": "{blocks {number}}"}`,
 			want: `{"errors":[{"message":"from block number must be specified","path":["blocks"]}],"data":null}`,
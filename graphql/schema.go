@@ -89,6 +89,31 @@ const schema string = `
         amount: Long!
     }
 
+    # CHANGE(taiko): AnchorInfo is the decoded TaikoL2.anchor or TaikoL2.anchorV2
+    # transaction of a L2 block.
+    type AnchorInfo {
+        # L1Height is the number of the L1 block the L2 block is anchored to.
+        l1Height: Long!
+        # L1Hash is the hash of the L1 block, only set by TaikoL2.anchor.
+        l1Hash: Bytes32
+        # L1StateRoot is the state root of the L1 block.
+        l1StateRoot: Bytes32!
+        # ParentGasUsed is the gas used by the parent L2 block.
+        parentGasUsed: Long!
+        # BaseFeeConfig is the base fee config, only set by TaikoL2.anchorV2.
+        baseFeeConfig: BaseFeeConfig
+    }
+
+    # CHANGE(taiko): BaseFeeConfig is the config used to calculate the base fee of
+    # a L2 block.
+    type BaseFeeConfig {
+        adjustmentQuotient: Long!
+        sharingPctg: Long!
+        gasIssuancePerSecond: Long!
+        minGasExcess: Long!
+        maxGasIssuancePerBlock: Long!
+    }
+
     # Transaction is an Ethereum transaction.
     type Transaction {
         # Hash is the hash of this transaction.
@@ -276,6 +301,10 @@ const schema string = `
         blobGasUsed: Long
         # ExcessBlobGas is a running total of blob gas consumed in excess of the target, prior to the block.
         excessBlobGas: Long
+        # CHANGE(taiko): AnchorInfo is the decoded anchor transaction of the block.
+        # If the chain is not a Taiko chain, or the block has no anchor
+        # transaction, this field will be null.
+        anchorInfo: AnchorInfo
     }
 
     # CallData represents the data associated with a local contract call.
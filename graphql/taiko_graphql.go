@@ -0,0 +1,80 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+)
+
+// AnchorInfo represents the arguments of the anchor transaction of a L2 block.
+type AnchorInfo struct {
+	info *taiko.AnchorInfo
+}
+
+func (a *AnchorInfo) L1Height(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(a.info.L1Height)
+}
+
+func (a *AnchorInfo) L1Hash(ctx context.Context) *common.Hash {
+	return a.info.L1Hash
+}
+
+func (a *AnchorInfo) L1StateRoot(ctx context.Context) common.Hash {
+	return a.info.L1StateRoot
+}
+
+func (a *AnchorInfo) ParentGasUsed(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(a.info.ParentGasUsed)
+}
+
+func (a *AnchorInfo) BaseFeeConfig(ctx context.Context) *BaseFeeConfig {
+	if a.info.BaseFeeConfig == nil {
+		return nil
+	}
+	return &BaseFeeConfig{a.info.BaseFeeConfig}
+}
+
+// BaseFeeConfig represents the base fee config passed to TaikoL2.anchorV2.
+type BaseFeeConfig struct {
+	config *taiko.BaseFeeConfig
+}
+
+func (c *BaseFeeConfig) AdjustmentQuotient(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(c.config.AdjustmentQuotient)
+}
+
+func (c *BaseFeeConfig) SharingPctg(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(c.config.SharingPctg)
+}
+
+func (c *BaseFeeConfig) GasIssuancePerSecond(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(c.config.GasIssuancePerSecond)
+}
+
+func (c *BaseFeeConfig) MinGasExcess(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(c.config.MinGasExcess)
+}
+
+func (c *BaseFeeConfig) MaxGasIssuancePerBlock(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(c.config.MaxGasIssuancePerBlock)
+}
+
+// AnchorInfo returns the decoded anchor transaction of the block, it is null if the
+// chain is not a Taiko chain, or the block has no anchor transaction.
+func (b *Block) AnchorInfo(ctx context.Context) (*AnchorInfo, error) {
+	engine, ok := b.r.backend.Engine().(*taiko.Taiko)
+	if !ok {
+		return nil, nil
+	}
+	block, err := b.resolve(ctx)
+	if err != nil || block == nil || len(block.Transactions()) == 0 {
+		return nil, err
+	}
+	info, err := engine.DecodeAnchorTx(block.Transactions()[0])
+	if err != nil {
+		return nil, nil
+	}
+	return &AnchorInfo{info}, nil
+}
@@ -0,0 +1,213 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/uint256"
+)
+
+// errBundleTooLarge is returned if a bundle doesn't fit in the remaining bytes of
+// the transactions list.
+var errBundleTooLarge = errors.New("bundle exceeds transactions list size")
+
+// l2Bundle wraps a bundle with the average effective tip it pays per gas.
+type l2Bundle struct {
+	*bundlepool.Bundle
+
+	index int          // Arrival order of the bundle
+	tip   *uint256.Int // Average effective tip per gas of the bundle
+}
+
+// l2Bundles represents the set of bundles to be simulated into the transactions
+// lists, ordered by the average tip they pay.
+type l2Bundles struct {
+	pending  []*l2Bundle // Bundles to be simulated into the current list
+	deferred []*l2Bundle // Bundles not fitting in the current list
+}
+
+// pendingBundles returns the bundles in the pool targeting the given L2 block, the
+// bundles paying less than the given minimum tip are left out.
+func (w *Miner) pendingBundles(number uint64, baseFee *big.Int, minTip uint64) *l2Bundles {
+	bundles := new(l2Bundles)
+	if w.bundlePool == nil {
+		return bundles
+	}
+	for i, bundle := range w.bundlePool.Pending(number) {
+		var (
+			fees = new(uint256.Int)
+			gas  uint64
+		)
+		for _, tx := range bundle.Txs {
+			tip, err := tx.EffectiveGasTip(baseFee)
+			if err != nil {
+				log.Trace("Ignoring underpriced bundle", "hash", bundle.Hash(), "err", err)
+				fees = nil
+				break
+			}
+			fees.Add(fees, new(uint256.Int).Mul(uint256.MustFromBig(tip), uint256.NewInt(tx.Gas())))
+			gas += tx.Gas()
+		}
+		if fees == nil || gas == 0 {
+			continue
+		}
+		tip := new(uint256.Int).Div(fees, uint256.NewInt(gas))
+		if tip.CmpUint64(minTip) < 0 {
+			log.Trace("Ignoring bundle with low tip", "hash", bundle.Hash(), "tip", tip, "minTip", minTip)
+			continue
+		}
+		bundles.pending = append(bundles.pending, &l2Bundle{Bundle: bundle, index: i, tip: tip})
+	}
+	bundles.sort()
+
+	return bundles
+}
+
+// sort orders the pending bundles by their tips, and their arrival order if the
+// tips are equal.
+func (b *l2Bundles) sort() {
+	slices.SortFunc(b.pending, func(x, y *l2Bundle) int {
+		if cmp := y.tip.Cmp(x.tip); cmp != 0 {
+			return cmp
+		}
+		return x.index - y.index
+	})
+}
+
+// next prepares the bundles for the next transactions list, retrying the ones not
+// fitting in the previous list.
+func (b *l2Bundles) next() {
+	b.pending, b.deferred = append(b.pending, b.deferred...), nil
+	b.sort()
+}
+
+// peek returns the next bundle to be simulated, if any.
+func (b *l2Bundles) peek() *l2Bundle {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	return b.pending[0]
+}
+
+// pop removes the next bundle.
+func (b *l2Bundles) pop() {
+	b.pending = b.pending[1:]
+}
+
+// postpone moves the next bundle to the following transactions list.
+func (b *l2Bundles) postpone() {
+	b.deferred = append(b.deferred, b.pending[0])
+	b.pending = b.pending[1:]
+}
+
+// commitL2Bundle commits all the transactions of the given bundle, or none of them
+// if any of them fails, reverts without being allowed to, or the bundle doesn't
+// fit in the bytes limit of the transactions list.
+func (w *Miner) commitL2Bundle(
+	env *environment,
+	bundle *l2Bundle,
+	codec TxListCodec,
+	maxBytesPerTxList uint64,
+) ([]*l2Transaction, error) {
+	// Check the bundle before running it, so that the state is left untouched if
+	// it can't be included anyway.
+	senders := make([]common.Address, len(bundle.Txs))
+	for i, tx := range bundle.Txs {
+		if w.isAnchorCall(tx) {
+			return nil, fmt.Errorf("anchor call %s", tx.Hash())
+		}
+		from, err := types.Sender(env.signer, tx)
+		if err != nil {
+			return nil, err
+		}
+		senders[i] = from
+	}
+	b, err := EncodeTxList(codec, append(slices.Clip(env.txs), bundle.Txs...))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(b)) > maxBytesPerTxList {
+		return nil, errBundleTooLarge
+	}
+
+	// A failing transaction is reverted with a snapshot of the state before being
+	// finalised. The journal of the state is cleared once a transaction is finalised
+	// though, so a copy of the state is still needed to revert the transactions
+	// already run, if the bundle has more than one.
+	var (
+		stateCopy *state.StateDB
+		gas       = env.gasPool.Gas()
+		gasUsed   = env.header.GasUsed
+		txs       = len(env.txs)
+		receipts  = len(env.receipts)
+		tcount    = env.tcount
+		committed []*l2Transaction
+	)
+	if len(bundle.Txs) > 1 {
+		stateCopy = env.state.Copy()
+	}
+	revert := func(err error) ([]*l2Transaction, error) {
+		if stateCopy != nil {
+			env.state = stateCopy
+		}
+		env.gasPool.SetGas(gas)
+		env.header.GasUsed = gasUsed
+		env.txs, env.receipts, env.tcount = env.txs[:txs], env.receipts[:receipts], tcount
+		return nil, err
+	}
+	for i, tx := range bundle.Txs {
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		receipt, err := w.applyL2BundleTransaction(env, tx, bundle.CanRevert(tx.Hash()))
+		if err != nil {
+			return revert(fmt.Errorf("transaction %s failed: %w", tx.Hash(), err))
+		}
+		env.txs = append(env.txs, tx)
+		env.receipts = append(env.receipts, receipt)
+		env.tcount++
+		committed = append(committed, w.newL2Transaction(env, tx, senders[i], receipt))
+	}
+
+	return committed, nil
+}
+
+// applyL2BundleTransaction runs a transaction of a bundle. If the execution fails,
+// or the transaction reverts without being allowed to, the state and the gas pool
+// are reverted.
+func (w *Miner) applyL2BundleTransaction(env *environment, tx *types.Transaction, canRevert bool) (*types.Receipt, error) {
+	var (
+		snap = env.state.Snapshot()
+		gp   = env.gasPool.Gas()
+	)
+	msg, err := core.TransactionToMessage(tx, env.signer, env.header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	msg.BasefeeSharingPctg = w.chainConfig.TaikoParamsAt(env.header.Number, env.header.Time).BasefeeSharingPctg(env.header.Extra)
+
+	var (
+		blockContext = core.NewEVMBlockContext(env.header, w.chain, &env.coinbase)
+		vmenv        = vm.NewEVM(blockContext, core.NewEVMTxContext(msg), env.state, w.chainConfig, vm.Config{})
+	)
+	result, err := core.ApplyMessage(vmenv, msg, env.gasPool)
+	if err == nil && result.Failed() && !canRevert {
+		err = fmt.Errorf("reverted: %w", result.Err)
+	}
+	if err != nil {
+		env.state.RevertToSnapshot(snap)
+		env.gasPool.SetGas(gp)
+		return nil, err
+	}
+	env.state.Finalise(true)
+	env.header.GasUsed += result.UsedGas
+
+	return core.MakeReceipt(vmenv, result, env.state, env.header.Number, env.header.Hash(), tx, env.header.GasUsed, nil), nil
+}
@@ -1,11 +1,15 @@
 package miner
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // PreBuiltTxList is a pre-built transaction list based on the latest chain state,
@@ -14,6 +18,81 @@ type PreBuiltTxList struct {
 	TxList           types.Transactions
 	EstimatedGasUsed uint64
 	BytesLength      uint64
+
+	// Blob usage of the compressed list, only set when it's budgeted in blobs.
+	BlobCount     uint64
+	FieldElements uint64
+
+	// Estimated economics of the list, based on its execution on top of the latest
+	// chain state.
+	TotalPriorityFees *big.Int // Priority fees paid to the coinbase
+	BaseFeeToCoinbase *big.Int // Base fee share paid to the coinbase
+	BaseFeeToTreasury *big.Int // Base fee share paid to the treasury
+	SenderCount       uint64
+	TxCount           uint64
+
+	// Hashes of the bundles included in the list.
+	Bundles []common.Hash
+}
+
+// BlobBudget limits the size of each transactions list by the EIP-4844 blobs it
+// will be posted in, instead of the raw compressed bytes length.
+type BlobBudget struct {
+	MaxBlobs             uint64 `json:"maxBlobs"`             // Maximum number of blobs per transactions list
+	BytesPerFieldElement uint64 `json:"bytesPerFieldElement"` // Usable bytes packed into a field element (0 = 31)
+	FieldElementsPerBlob uint64 `json:"fieldElementsPerBlob"` // Number of field elements in a blob (0 = 4096)
+}
+
+// bytesPerFieldElement returns the usable bytes of each field element, by default
+// all bytes but the first one, to keep the field element below the BLS modulus.
+func (b *BlobBudget) bytesPerFieldElement() uint64 {
+	if b.BytesPerFieldElement == 0 {
+		return params.BlobTxBytesPerFieldElement - 1
+	}
+	return b.BytesPerFieldElement
+}
+
+// fieldElementsPerBlob returns the number of field elements in each blob.
+func (b *BlobBudget) fieldElementsPerBlob() uint64 {
+	if b.FieldElementsPerBlob == 0 {
+		return params.BlobTxFieldElementsPerBlob
+	}
+	return b.FieldElementsPerBlob
+}
+
+// validate checks whether the budget rules are sane.
+func (b *BlobBudget) validate() error {
+	if b.MaxBlobs == 0 {
+		return errors.New("blob budget without blobs")
+	}
+	if b.bytesPerFieldElement() > params.BlobTxBytesPerFieldElement {
+		return fmt.Errorf("too many bytes per field element: %d", b.BytesPerFieldElement)
+	}
+	return nil
+}
+
+// MaxBytes returns the byte capacity of the maximum number of blobs.
+func (b *BlobBudget) MaxBytes() uint64 {
+	return b.MaxBlobs * b.fieldElementsPerBlob() * b.bytesPerFieldElement()
+}
+
+// FieldElements returns the number of field elements needed to pack the given
+// number of bytes.
+func (b *BlobBudget) FieldElements(size uint64) uint64 {
+	perFieldElement := b.bytesPerFieldElement()
+	return (size + perFieldElement - 1) / perFieldElement
+}
+
+// Blobs returns the number of blobs needed to pack the given number of bytes.
+func (b *BlobBudget) Blobs(size uint64) uint64 {
+	perBlob := b.fieldElementsPerBlob()
+	return (b.FieldElements(size) + perBlob - 1) / perBlob
+}
+
+// SetBundlePool sets the pool of the bundles, which are simulated into the built
+// transactions lists.
+func (miner *Miner) SetBundlePool(pool *bundlepool.BundlePool) {
+	miner.bundlePool = pool
 }
 
 // SealBlockWith mines and seals a block without changing the canonical chain.
@@ -27,44 +106,59 @@ func (miner *Miner) SealBlockWith(
 	return miner.sealBlockWith(parent, timestamp, blkMeta, baseFeePerGas, withdrawals)
 }
 
-// BuildTransactionsLists builds multiple transactions lists which satisfy all the given limits.
+// BuildTransactionsLists builds multiple transactions lists which satisfy all the given limits,
+// the lists are budgeted in blobs instead of bytes if the blob budget is not nil. The pending
+// transactions are ordered by the named strategy, or by price and nonce if it's empty. The
+// fees are estimated with the extradata of the block the lists will be proposed in.
 func (miner *Miner) BuildTransactionsLists(
 	beneficiary common.Address,
 	baseFee *big.Int,
+	extraData []byte,
 	blockMaxGasLimit uint64,
 	maxBytesPerTxList uint64,
 	locals []string,
 	maxTransactionsLists uint64,
+	blobBudget *BlobBudget,
+	orderingStrategy string,
 ) ([]*PreBuiltTxList, error) {
 	return miner.buildTransactionsLists(
 		beneficiary,
 		baseFee,
+		extraData,
 		blockMaxGasLimit,
 		maxBytesPerTxList,
 		locals,
 		maxTransactionsLists,
 		0,
+		blobBudget,
+		orderingStrategy,
 	)
 }
 
 // BuildTransactionsListsWithMinTip builds multiple transactions lists which satisfy all
-// the given limits and minimum tip.
+// the given limits and minimum tip, ordered by the named strategy.
 func (miner *Miner) BuildTransactionsListsWithMinTip(
 	beneficiary common.Address,
 	baseFee *big.Int,
+	extraData []byte,
 	blockMaxGasLimit uint64,
 	maxBytesPerTxList uint64,
 	locals []string,
 	maxTransactionsLists uint64,
 	minTip uint64,
+	blobBudget *BlobBudget,
+	orderingStrategy string,
 ) ([]*PreBuiltTxList, error) {
 	return miner.buildTransactionsLists(
 		beneficiary,
 		baseFee,
+		extraData,
 		blockMaxGasLimit,
 		maxBytesPerTxList,
 		locals,
 		maxTransactionsLists,
 		minTip,
+		blobBudget,
+		orderingStrategy,
 	)
 }
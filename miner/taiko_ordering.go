@@ -0,0 +1,352 @@
+package miner
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+)
+
+const (
+	// TxOrderingPriceAndNonce orders the transactions by their effective tip, like
+	// the L1 miner does. It's the default ordering strategy.
+	TxOrderingPriceAndNonce = "priceAndNonce"
+
+	// TxOrderingFIFO orders the transactions strictly by the time they arrived.
+	TxOrderingFIFO = "fifo"
+
+	// TxOrderingFeePerByte orders the transactions by the fee revenue per byte
+	// they occupy in the compressed transactions list.
+	TxOrderingFeePerByte = "feePerByte"
+
+	// TxOrderingSenderCap orders the transactions by their effective tip, but caps
+	// the number of transactions of each sender in a transactions list. The cap is
+	// defaultSenderCap, or given as the parameter of the strategy, e.g. "senderCap:8".
+	TxOrderingSenderCap = "senderCap"
+)
+
+// defaultSenderCap is the maximum number of transactions of a sender in each
+// transactions list, when ordering with the TxOrderingSenderCap strategy.
+const defaultSenderCap = 16
+
+// TxOrdering is a set of pending transactions, which are retrieved in the order of
+// an ordering strategy, while honouring the nonces of each account.
+type TxOrdering interface {
+	// Peek returns the next transaction and its effective miner tip.
+	Peek() (*txpool.LazyTransaction, *uint256.Int)
+
+	// Shift replaces the next transaction with the following one from the same
+	// account.
+	Shift()
+
+	// Pop removes the next transaction, discarding all the following ones from the
+	// same account.
+	Pop()
+}
+
+// txOrderingStrategyWithParam is a strategy taking a parameter after its name in the
+// RPC requests, separated by a colon.
+type txOrderingStrategyWithParam interface {
+	TxOrderingStrategy
+
+	// withParam returns the strategy configured with the given parameter.
+	withParam(param string) (TxOrderingStrategy, error)
+}
+
+// TxOrderingStrategy decides the order in which the pending transactions are
+// committed into the transactions lists.
+type TxOrderingStrategy interface {
+	// Name returns the name of the strategy used in the RPC requests.
+	Name() string
+
+	// Order creates the ordered set of the given nonce-sorted transactions of each
+	// account, which are compressed with the given codec in the transactions lists.
+	//
+	// Note, the input map is reowned so the caller should not interact any more
+	// with it after providing it to the strategy.
+	Order(
+		signer types.Signer,
+		txs map[common.Address][]*txpool.LazyTransaction,
+		baseFee *big.Int,
+		codec TxListCodec,
+	) TxOrdering
+}
+
+var (
+	txOrderingStrategiesLock sync.RWMutex
+	txOrderingStrategies     = map[string]TxOrderingStrategy{
+		TxOrderingPriceAndNonce: priceAndNonceStrategy{},
+		TxOrderingFIFO:          fifoStrategy{},
+		TxOrderingFeePerByte:    feePerByteStrategy{},
+		TxOrderingSenderCap:     senderCapStrategy{cap: defaultSenderCap},
+	}
+)
+
+// RegisterTxOrderingStrategy makes the given strategy available to the transactions
+// lists builder, it replaces the registered strategy with the same name, if any.
+func RegisterTxOrderingStrategy(strategy TxOrderingStrategy) {
+	txOrderingStrategiesLock.Lock()
+	defer txOrderingStrategiesLock.Unlock()
+
+	txOrderingStrategies[strategy.Name()] = strategy
+}
+
+// TxOrderingStrategyByName returns the registered strategy with the given name, the
+// empty name stands for the default TxOrderingPriceAndNonce strategy. The name might
+// be followed by a parameter of the strategy, e.g. "senderCap:8".
+func TxOrderingStrategyByName(name string) (TxOrderingStrategy, error) {
+	txOrderingStrategiesLock.RLock()
+	defer txOrderingStrategiesLock.RUnlock()
+
+	if name == "" {
+		name = TxOrderingPriceAndNonce
+	}
+	name, param, hasParam := strings.Cut(name, ":")
+	strategy, ok := txOrderingStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tx ordering strategy: %s", name)
+	}
+	if !hasParam {
+		return strategy, nil
+	}
+	withParam, ok := strategy.(txOrderingStrategyWithParam)
+	if !ok {
+		return nil, fmt.Errorf("tx ordering strategy %s takes no parameter", name)
+	}
+	return withParam.withParam(param)
+}
+
+// priceAndNonceStrategy is the TxOrderingPriceAndNonce strategy.
+type priceAndNonceStrategy struct{}
+
+// Name implements TxOrderingStrategy.
+func (priceAndNonceStrategy) Name() string { return TxOrderingPriceAndNonce }
+
+// Order implements TxOrderingStrategy.
+func (priceAndNonceStrategy) Order(
+	signer types.Signer,
+	txs map[common.Address][]*txpool.LazyTransaction,
+	baseFee *big.Int,
+	codec TxListCodec,
+) TxOrdering {
+	return newTransactionsByPriceAndNonce(signer, txs, baseFee)
+}
+
+// senderCapStrategy is the TxOrderingSenderCap strategy.
+type senderCapStrategy struct {
+	cap int
+}
+
+// Name implements TxOrderingStrategy.
+func (senderCapStrategy) Name() string { return TxOrderingSenderCap }
+
+// withParam implements txOrderingStrategyWithParam, the parameter is the cap.
+func (senderCapStrategy) withParam(param string) (TxOrderingStrategy, error) {
+	n, err := strconv.Atoi(param)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid sender cap: %q", param)
+	}
+	return senderCapStrategy{cap: n}, nil
+}
+
+// Order implements TxOrderingStrategy, only the first transactions of each account
+// up to the cap are ordered.
+func (s senderCapStrategy) Order(
+	signer types.Signer,
+	txs map[common.Address][]*txpool.LazyTransaction,
+	baseFee *big.Int,
+	codec TxListCodec,
+) TxOrdering {
+	for from, accTxs := range txs {
+		if len(accTxs) > s.cap {
+			txs[from] = accTxs[:s.cap]
+		}
+	}
+	return newTransactionsByPriceAndNonce(signer, txs, baseFee)
+}
+
+// fifoStrategy is the TxOrderingFIFO strategy.
+type fifoStrategy struct{}
+
+// Name implements TxOrderingStrategy.
+func (fifoStrategy) Name() string { return TxOrderingFIFO }
+
+// Order implements TxOrderingStrategy, the transactions which arrived at the same
+// time are ordered by their hashes to keep the order deterministic.
+func (fifoStrategy) Order(
+	signer types.Signer,
+	txs map[common.Address][]*txpool.LazyTransaction,
+	baseFee *big.Int,
+	codec TxListCodec,
+) TxOrdering {
+	return newTransactionsByScoreAndNonce(txs, baseFee, nil, func(a, b *scoredTx) bool {
+		if !a.tx.Time.Equal(b.tx.Time) {
+			return a.tx.Time.Before(b.tx.Time)
+		}
+		return bytes.Compare(a.tx.Hash[:], b.tx.Hash[:]) < 0
+	})
+}
+
+// feePerByteStrategy is the TxOrderingFeePerByte strategy.
+type feePerByteStrategy struct{}
+
+// Name implements TxOrderingStrategy.
+func (feePerByteStrategy) Name() string { return TxOrderingFeePerByte }
+
+// Order implements TxOrderingStrategy. The fee revenue of a transaction is its
+// effective tip times its gas limit, and the bytes it occupies are approximated by
+// compressing the transaction on its own.
+func (feePerByteStrategy) Order(
+	signer types.Signer,
+	txs map[common.Address][]*txpool.LazyTransaction,
+	baseFee *big.Int,
+	codec TxListCodec,
+) TxOrdering {
+	score := func(tx *scoredTx) {
+		tx.revenue = new(uint256.Int).Mul(tx.fees, uint256.NewInt(tx.tx.Gas))
+		tx.size = 1
+		if resolved := tx.tx.Resolve(); resolved != nil {
+			if b, err := rlp.EncodeToBytes(resolved); err == nil {
+				if b, err = codec.Compress(b); err == nil && len(b) > 0 {
+					tx.size = uint64(len(b))
+				}
+			}
+		}
+	}
+	return newTransactionsByScoreAndNonce(txs, baseFee, score, func(a, b *scoredTx) bool {
+		// Compare a.revenue / a.size with b.revenue / b.size without divisions.
+		var (
+			x = new(big.Int).Mul(a.revenue.ToBig(), new(big.Int).SetUint64(b.size))
+			y = new(big.Int).Mul(b.revenue.ToBig(), new(big.Int).SetUint64(a.size))
+		)
+		if cmp := x.Cmp(y); cmp != 0 {
+			return cmp > 0
+		}
+		if !a.tx.Time.Equal(b.tx.Time) {
+			return a.tx.Time.Before(b.tx.Time)
+		}
+		return bytes.Compare(a.tx.Hash[:], b.tx.Hash[:]) < 0
+	})
+}
+
+// scoredTx wraps a transaction with its effective miner tip, and the values an
+// ordering strategy scores it by.
+type scoredTx struct {
+	*txWithMinerFee
+
+	revenue *uint256.Int // Fee revenue of the transaction
+	size    uint64       // Bytes occupied by the transaction
+}
+
+// txByScore implements the heap interface, ordering the transactions with the
+// given comparison function.
+type txByScore struct {
+	txs  []*scoredTx
+	less func(a, b *scoredTx) bool
+}
+
+func (s *txByScore) Len() int           { return len(s.txs) }
+func (s *txByScore) Less(i, j int) bool { return s.less(s.txs[i], s.txs[j]) }
+func (s *txByScore) Swap(i, j int)      { s.txs[i], s.txs[j] = s.txs[j], s.txs[i] }
+
+func (s *txByScore) Push(x interface{}) {
+	s.txs = append(s.txs, x.(*scoredTx))
+}
+
+func (s *txByScore) Pop() interface{} {
+	old := s.txs
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	s.txs = old[0 : n-1]
+	return x
+}
+
+// transactionsByScoreAndNonce represents a set of transactions that can return
+// transactions in the order of their scores, while honouring the nonces of each
+// account.
+type transactionsByScoreAndNonce struct {
+	txs     map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
+	heads   *txByScore                                   // Next transaction for each unique account (score heap)
+	baseFee *uint256.Int                                 // Current base fee
+	score   func(tx *scoredTx)                           // Fills the scores of a transaction, if any
+}
+
+// newTransactionsByScoreAndNonce creates a transaction set ordered by the given
+// comparison function, the score function is called on every wrapped transaction
+// before it's compared.
+func newTransactionsByScoreAndNonce(
+	txs map[common.Address][]*txpool.LazyTransaction,
+	baseFee *big.Int,
+	score func(tx *scoredTx),
+	less func(a, b *scoredTx) bool,
+) *transactionsByScoreAndNonce {
+	t := &transactionsByScoreAndNonce{
+		txs:   txs,
+		heads: &txByScore{txs: make([]*scoredTx, 0, len(txs)), less: less},
+		score: score,
+	}
+	if baseFee != nil {
+		t.baseFee = uint256.MustFromBig(baseFee)
+	}
+	for from, accTxs := range txs {
+		wrapped, err := t.wrap(accTxs[0], from)
+		if err != nil {
+			delete(txs, from)
+			continue
+		}
+		t.heads.txs = append(t.heads.txs, wrapped)
+		txs[from] = accTxs[1:]
+	}
+	heap.Init(t.heads)
+
+	return t
+}
+
+// wrap wraps and scores the given transaction.
+func (t *transactionsByScoreAndNonce) wrap(tx *txpool.LazyTransaction, from common.Address) (*scoredTx, error) {
+	withFee, err := newTxWithMinerFee(tx, from, t.baseFee)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &scoredTx{txWithMinerFee: withFee}
+	if t.score != nil {
+		t.score(wrapped)
+	}
+	return wrapped, nil
+}
+
+// Peek implements TxOrdering.
+func (t *transactionsByScoreAndNonce) Peek() (*txpool.LazyTransaction, *uint256.Int) {
+	if len(t.heads.txs) == 0 {
+		return nil, nil
+	}
+	return t.heads.txs[0].tx, t.heads.txs[0].fees
+}
+
+// Shift implements TxOrdering.
+func (t *transactionsByScoreAndNonce) Shift() {
+	acc := t.heads.txs[0].from
+	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
+		if wrapped, err := t.wrap(txs[0], acc); err == nil {
+			t.heads.txs[0], t.txs[acc] = wrapped, txs[1:]
+			heap.Fix(t.heads, 0)
+			return
+		}
+	}
+	heap.Pop(t.heads)
+}
+
+// Pop implements TxOrdering.
+func (t *transactionsByScoreAndNonce) Pop() {
+	heap.Pop(t.heads)
+}
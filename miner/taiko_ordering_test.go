@@ -0,0 +1,152 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLazyTx creates a lazy transaction with the given nonce, tip, payload size
+// and arrival time.
+func newTestLazyTx(nonce uint64, tip int64, size int, seen int64) *txpool.LazyTransaction {
+	// Use hashes as the payload, so that it's not compressible.
+	var data []byte
+	for seed := crypto.Keccak256([]byte{byte(nonce)}); len(data) < size; seed = crypto.Keccak256(seed) {
+		data = append(data, seed...)
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		Nonce:     nonce,
+		To:        &common.Address{},
+		Gas:       100_000,
+		GasFeeCap: big.NewInt(tip),
+		GasTipCap: big.NewInt(tip),
+		Data:      data[:size],
+	})
+	return &txpool.LazyTransaction{
+		Hash:      tx.Hash(),
+		Tx:        tx,
+		Time:      time.Unix(seen, 0),
+		GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+		GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+		Gas:       tx.Gas(),
+	}
+}
+
+// orderTestTxs orders the given transactions with the named strategy, and returns
+// the hashes of all the ordered transactions.
+func orderTestTxs(t *testing.T, name string, txs map[common.Address][]*txpool.LazyTransaction) []common.Hash {
+	strategy, err := TxOrderingStrategyByName(name)
+	assert.NoError(t, err)
+
+	var (
+		ordering = strategy.Order(types.LatestSignerForChainID(common.Big1), txs, common.Big0, zlibTxListCodec{})
+		hashes   []common.Hash
+	)
+	for ltx, _ := ordering.Peek(); ltx != nil; ltx, _ = ordering.Peek() {
+		hashes = append(hashes, ltx.Hash)
+		ordering.Shift()
+	}
+	return hashes
+}
+
+func TestTxOrderingStrategyByName(t *testing.T) {
+	for _, name := range []string{TxOrderingPriceAndNonce, TxOrderingFIFO, TxOrderingFeePerByte, TxOrderingSenderCap} {
+		strategy, err := TxOrderingStrategyByName(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, strategy.Name())
+	}
+	strategy, err := TxOrderingStrategyByName("")
+	assert.NoError(t, err)
+	assert.Equal(t, TxOrderingPriceAndNonce, strategy.Name())
+
+	_, err = TxOrderingStrategyByName("unknown")
+	assert.Error(t, err)
+
+	strategy, err = TxOrderingStrategyByName("senderCap:8")
+	assert.NoError(t, err)
+	assert.Equal(t, senderCapStrategy{cap: 8}, strategy)
+
+	for _, name := range []string{"senderCap:", "senderCap:0", "senderCap:-1", "senderCap:x", "fifo:1", "unknown:1"} {
+		_, err = TxOrderingStrategyByName(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestTxOrderingFIFO(t *testing.T) {
+	var (
+		a0 = newTestLazyTx(0, 1, 0, 3)
+		a1 = newTestLazyTx(1, 100, 0, 1) // Arrived first, but its nonce is blocked by a0
+		b0 = newTestLazyTx(0, 50, 0, 2)
+		c0 = newTestLazyTx(2, 10, 0, 2) // Arrived with b0, ordered by hash
+	)
+	first, second := b0, c0
+	if c0.Hash.Cmp(b0.Hash) < 0 {
+		first, second = c0, b0
+	}
+	hashes := orderTestTxs(t, TxOrderingFIFO, map[common.Address][]*txpool.LazyTransaction{
+		{0x0a}: {a0, a1},
+		{0x0b}: {b0},
+		{0x0c}: {c0},
+	})
+	assert.Equal(t, []common.Hash{first.Hash, second.Hash, a0.Hash, a1.Hash}, hashes)
+}
+
+func TestTxOrderingFeePerByte(t *testing.T) {
+	var (
+		large = newTestLazyTx(0, 10, 1024, 1) // Highest tip, but the lowest tip per byte
+		small = newTestLazyTx(1, 5, 0, 2)
+		tied  = newTestLazyTx(2, 5, 0, 1) // Same as small, but arrived earlier
+	)
+	// The payload is not compressible, the bigger list loses.
+	hashes := orderTestTxs(t, TxOrderingFeePerByte, map[common.Address][]*txpool.LazyTransaction{
+		{0x0a}: {large},
+		{0x0b}: {small},
+		{0x0c}: {tied},
+	})
+	assert.Equal(t, []common.Hash{tied.Hash, small.Hash, large.Hash}, hashes)
+
+	// The price and nonce ordering prefers the highest tip instead.
+	hashes = orderTestTxs(t, TxOrderingPriceAndNonce, map[common.Address][]*txpool.LazyTransaction{
+		{0x0a}: {large},
+		{0x0b}: {small},
+	})
+	assert.Equal(t, []common.Hash{large.Hash, small.Hash}, hashes)
+}
+
+func TestTxOrderingSenderCap(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		txs  int
+		want int
+	}{
+		{TxOrderingSenderCap, defaultSenderCap, defaultSenderCap},
+		{TxOrderingSenderCap, defaultSenderCap + 1, defaultSenderCap},
+		{"senderCap:2", 2, 2},
+		{"senderCap:2", 3, 2},
+		{"senderCap:32", 2 * defaultSenderCap, 2 * defaultSenderCap},
+	} {
+		var (
+			greedy []*txpool.LazyTransaction
+			fair   = newTestLazyTx(0, 1, 0, 1)
+		)
+		for i := 0; i < tt.txs; i++ {
+			greedy = append(greedy, newTestLazyTx(uint64(i), 100, 0, 1))
+		}
+		hashes := orderTestTxs(t, tt.name, map[common.Address][]*txpool.LazyTransaction{
+			{0x0a}: greedy,
+			{0x0b}: {fair},
+		})
+		var want []common.Hash
+		for _, tx := range greedy[:tt.want] {
+			want = append(want, tx.Hash)
+		}
+		assert.Equal(t, append(want, fair.Hash), hashes, "%s with %d txs", tt.name, tt.txs)
+	}
+}
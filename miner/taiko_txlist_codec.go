@@ -0,0 +1,125 @@
+package miner
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TxListCodec compresses the RLP encoded transactions lists proposed to L1. The
+// codec of each fork is chosen by its name in the chain config.
+type TxListCodec interface {
+	// Name returns the name of the codec used in the chain config.
+	Name() string
+
+	// Compress compresses the given RLP encoded transactions list.
+	Compress(txListBytes []byte) ([]byte, error)
+
+	// Decompress decompresses the given bytes back into the RLP encoded
+	// transactions list.
+	Decompress(b []byte) ([]byte, error)
+}
+
+var (
+	txListCodecsLock sync.RWMutex
+	txListCodecs     = map[string]TxListCodec{
+		params.TxListCodecZlib: zlibTxListCodec{},
+	}
+)
+
+// RegisterTxListCodec makes the given codec available to be chosen in the chain
+// config, it replaces the registered codec with the same name, if any.
+func RegisterTxListCodec(codec TxListCodec) {
+	txListCodecsLock.Lock()
+	defer txListCodecsLock.Unlock()
+
+	txListCodecs[codec.Name()] = codec
+}
+
+// TxListCodecAt returns the codec compressing the transactions lists proposed for
+// the given L2 block.
+func TxListCodecAt(config *params.ChainConfig, num *big.Int, time uint64) (TxListCodec, error) {
+	txListCodecsLock.RLock()
+	defer txListCodecsLock.RUnlock()
+
+	name := config.TxListCodecAt(num, time)
+	codec, ok := txListCodecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown txList codec: %s", name)
+	}
+	return codec, nil
+}
+
+// EncodeTxList encodes and compresses the given transactions list.
+func EncodeTxList(codec TxListCodec, txs types.Transactions) ([]byte, error) {
+	b, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.Compress(b)
+}
+
+// DecodeTxList decompresses and decodes the given transactions list, which is the
+// inverse of EncodeTxList.
+func DecodeTxList(codec TxListCodec, b []byte) (types.Transactions, error) {
+	b, err := codec.Decompress(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress txList with %s: %w", codec.Name(), err)
+	}
+
+	var txs types.Transactions
+	if err := rlp.DecodeBytes(b, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// zlibTxListCodec is the zlib transactions list codec.
+type zlibTxListCodec struct{}
+
+// Name implements TxListCodec.
+func (zlibTxListCodec) Name() string { return params.TxListCodecZlib }
+
+// Compress implements TxListCodec, compressing the given txList bytes using zlib.
+// The stream is flushed but not closed, so the end of the stream and its checksum
+// are left out of the proposed bytes.
+func (zlibTxListCodec) Compress(txListBytes []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	defer w.Close()
+
+	if _, err := w.Write(txListBytes); err != nil {
+		return nil, err
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Decompress implements TxListCodec, the unexpected end of a stream which was not
+// closed is tolerated.
+func (zlibTxListCodec) Decompress(b []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,128 @@
+package miner
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reverseTxListCodec is a testing codec which reverses the txList bytes.
+type reverseTxListCodec struct{}
+
+func (reverseTxListCodec) Name() string { return "reverse" }
+
+func (reverseTxListCodec) Compress(txListBytes []byte) ([]byte, error) {
+	b := make([]byte, len(txListBytes))
+	for i, c := range txListBytes {
+		b[len(b)-1-i] = c
+	}
+	return b, nil
+}
+
+func (c reverseTxListCodec) Decompress(b []byte) ([]byte, error) { return c.Compress(b) }
+
+func newTestTxList(n int) types.Transactions {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		tx, _ := types.SignTx(
+			types.NewTransaction(uint64(i), testUserAddress, big.NewInt(1000), params.TxGas, big.NewInt(params.InitialBaseFee), nil),
+			types.HomesteadSigner{},
+			testBankKey,
+		)
+		txs[i] = tx
+	}
+	return txs
+}
+
+func TestZlibTxListCodecRoundTrip(t *testing.T) {
+	codec, err := TxListCodecAt(&params.ChainConfig{}, big.NewInt(1), 0)
+	require.NoError(t, err)
+	assert.Equal(t, params.TxListCodecZlib, codec.Name())
+
+	for _, n := range []int{0, 1, 100} {
+		txs := newTestTxList(n)
+
+		b, err := EncodeTxList(codec, txs)
+		require.NoError(t, err)
+
+		decoded, err := DecodeTxList(codec, b)
+		require.NoError(t, err)
+		require.Len(t, decoded, n)
+		for i := range txs {
+			assert.Equal(t, txs[i].Hash(), decoded[i].Hash())
+		}
+
+		// The plain RLP encoded txList is not compressed, so it's rejected.
+		plain, err := rlp.EncodeToBytes(txs)
+		require.NoError(t, err)
+		_, err = DecodeTxList(codec, plain)
+		assert.ErrorContains(t, err, "failed to decompress")
+	}
+
+	_, err = DecodeTxList(codec, []byte{0x01, 0x02, 0x03})
+	assert.Error(t, err)
+}
+
+// Tests that the zlib codec keeps the output of the original txList compression,
+// which flushes the stream without closing it.
+func TestZlibTxListCodecOutput(t *testing.T) {
+	codec := zlibTxListCodec{}
+	for _, tt := range []struct {
+		input []byte
+		want  string
+	}{
+		{[]byte{0xc0}, "789c000100feffc0000000ffff"},
+		{[]byte("taiko taiko taiko"), "789c001100eeff7461696b6f207461696b6f207461696b6f000000ffff"},
+	} {
+		b, err := codec.Compress(tt.input)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, hex.EncodeToString(b))
+
+		decompressed, err := codec.Decompress(b)
+		require.NoError(t, err)
+		assert.Equal(t, tt.input, decompressed)
+	}
+
+	// Closed streams, with the checksum, are decompressed too.
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	_, err := w.Write([]byte("taiko"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	decompressed, err := codec.Decompress(b.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("taiko"), decompressed)
+}
+
+func TestTxListCodecByFork(t *testing.T) {
+	RegisterTxListCodec(reverseTxListCodec{})
+
+	config := &params.ChainConfig{OntakeBlock: big.NewInt(2), TxListCodecs: map[string]string{"ontake": "reverse"}}
+
+	codec, err := TxListCodecAt(config, big.NewInt(1), 0)
+	require.NoError(t, err)
+	assert.Equal(t, params.TxListCodecZlib, codec.Name())
+
+	codec, err = TxListCodecAt(config, big.NewInt(2), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "reverse", codec.Name())
+
+	txs := newTestTxList(10)
+	b, err := EncodeTxList(codec, txs)
+	require.NoError(t, err)
+	decoded, err := DecodeTxList(codec, b)
+	require.NoError(t, err)
+	assert.Equal(t, txs[9].Hash(), decoded[9].Hash())
+
+	_, err = TxListCodecAt(&params.ChainConfig{TxListCodecs: map[string]string{"genesis": "unknown"}}, big.NewInt(1), 0)
+	assert.Error(t, err)
+}
@@ -1,17 +1,16 @@
 package miner
 
 import (
-	"bytes"
-	"compress/zlib"
 	"errors"
 	"fmt"
-	"maps"
 	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -27,15 +26,38 @@ import (
 // 2. The total gas used should not exceed the given blockMaxGasLimit
 // 3. The total bytes used should not exceed the given maxBytesPerTxList
 // 4. The total number of transactions lists should not exceed the given maxTransactionsLists
+// 5. The compressed bytes should fit in the given blob budget, if any
+// The fees are estimated with the given extradata, which carries the base fee sharing
+// percentage of the block the lists will be proposed in. The pending transactions are committed in the order of the given strategy, and the
+// pending bundles are simulated as units in between them.
 func (w *Miner) buildTransactionsLists(
 	beneficiary common.Address,
 	baseFee *big.Int,
+	extraData []byte,
 	blockMaxGasLimit uint64,
 	maxBytesPerTxList uint64,
 	localAccounts []string,
 	maxTransactionsLists uint64,
 	minTip uint64,
+	blobBudget *BlobBudget,
+	orderingStrategy string,
 ) ([]*PreBuiltTxList, error) {
+	strategy, err := TxOrderingStrategyByName(orderingStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Budget the transactions lists in blobs, without exceeding the bytes limit if
+	// it's also given.
+	if blobBudget != nil {
+		if err := blobBudget.validate(); err != nil {
+			return nil, err
+		}
+		if maxBytes := blobBudget.MaxBytes(); maxBytesPerTxList == 0 || maxBytes < maxBytesPerTxList {
+			maxBytesPerTxList = maxBytes
+		}
+	}
+
 	var (
 		txsLists    []*PreBuiltTxList
 		currentHead = w.chain.CurrentBlock()
@@ -64,43 +86,74 @@ func (w *Miner) buildTransactionsLists(
 	if err != nil {
 		return nil, err
 	}
+	// Execute the transactions with the extradata of the proposed block instead of
+	// the one of the miner, so the base fee is shared as it will be on chain.
+	env.header.Extra = extraData
+
+	codec, err := TxListCodecAt(w.chainConfig, env.header.Number, env.header.Time)
+	if err != nil {
+		return nil, err
+	}
 
 	var (
 		signer = types.MakeSigner(w.chainConfig, new(big.Int).Add(currentHead.Number, common.Big1), currentHead.Time)
 		// Split the pending transactions into locals and remotes, then
 		// fill the block with all available pending transactions.
 		localTxs, remoteTxs = w.getPendingTxs(localAccounts, baseFee)
+		bundles             = w.pendingBundles(env.header.Number.Uint64(), baseFee, minTip)
 	)
 
-	commitTxs := func(firstTransaction *types.Transaction) (*types.Transaction, *PreBuiltTxList, error) {
+	commitTxs := func(firstTransaction *l2Transaction) (*l2Transaction, *PreBuiltTxList, error) {
 		env.tcount = 0
 		env.txs = []*types.Transaction{}
 		env.gasPool = new(core.GasPool).AddGas(blockMaxGasLimit)
 		env.header.GasLimit = blockMaxGasLimit
+		bundles.next()
 
-		lastTransaction := w.commitL2Transactions(
+		lastTransaction, committed, landed := w.commitL2Transactions(
 			env,
 			firstTransaction,
-			newTransactionsByPriceAndNonce(signer, maps.Clone(localTxs), baseFee),
-			newTransactionsByPriceAndNonce(signer, maps.Clone(remoteTxs), baseFee),
+			strategy.Order(signer, dropCommittedTxs(env, localTxs), baseFee, codec),
+			strategy.Order(signer, dropCommittedTxs(env, remoteTxs), baseFee, codec),
+			bundles,
+			codec,
 			maxBytesPerTxList,
 			minTip,
 		)
 
-		b, err := encodeAndCompressTxList(env.txs)
+		b, err := EncodeTxList(codec, env.txs)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		return lastTransaction, &PreBuiltTxList{
-			TxList:           env.txs,
-			EstimatedGasUsed: env.header.GasLimit - env.gasPool.Gas(),
-			BytesLength:      uint64(len(b)),
-		}, nil
+		txList := &PreBuiltTxList{
+			TxList:            env.txs,
+			EstimatedGasUsed:  env.header.GasLimit - env.gasPool.Gas(),
+			BytesLength:       uint64(len(b)),
+			TotalPriorityFees: new(big.Int),
+			BaseFeeToCoinbase: new(big.Int),
+			BaseFeeToTreasury: new(big.Int),
+			TxCount:           uint64(len(env.txs)),
+			Bundles:           landed,
+		}
+		senders := make(map[common.Address]struct{})
+		for _, tx := range committed {
+			txList.TotalPriorityFees.Add(txList.TotalPriorityFees, tx.priorityFee)
+			txList.BaseFeeToCoinbase.Add(txList.BaseFeeToCoinbase, tx.baseFeeToCoinbase)
+			txList.BaseFeeToTreasury.Add(txList.BaseFeeToTreasury, tx.baseFeeToTreasury)
+			senders[tx.sender] = struct{}{}
+		}
+		txList.SenderCount = uint64(len(senders))
+		if blobBudget != nil {
+			txList.BlobCount = blobBudget.Blobs(txList.BytesLength)
+			txList.FieldElements = blobBudget.FieldElements(txList.BytesLength)
+		}
+
+		return lastTransaction, txList, nil
 	}
 
 	var (
-		lastTx *types.Transaction
+		lastTx *l2Transaction
 		res    *PreBuiltTxList
 	)
 	for i := 0; i < int(maxTransactionsLists); i++ {
@@ -126,17 +179,6 @@ func (w *Miner) sealBlockWith(
 	baseFeePerGas *big.Int,
 	withdrawals types.Withdrawals,
 ) (*types.Block, error) {
-	// Decode transactions bytes.
-	var txs types.Transactions
-	if err := rlp.DecodeBytes(blkMeta.TxList, &txs); err != nil {
-		return nil, fmt.Errorf("failed to decode txList: %w", err)
-	}
-
-	if len(txs) == 0 {
-		// A L2 block needs to have have at least one `TaikoL2.anchor` / `TaikoL2.anchorV2`.
-		return nil, fmt.Errorf("too less transactions in the block")
-	}
-
 	params := &generateParams{
 		timestamp:     timestamp,
 		forceTime:     true,
@@ -156,6 +198,18 @@ func (w *Miner) sealBlockWith(
 		return nil, err
 	}
 
+	// Decode transactions bytes, the txList is never compressed here, the proposed
+	// txList is decompressed with the codec of the fork by the caller.
+	var txs types.Transactions
+	if err := rlp.DecodeBytes(blkMeta.TxList, &txs); err != nil {
+		return nil, fmt.Errorf("failed to decode txList: %w", err)
+	}
+
+	if len(txs) == 0 {
+		// A L2 block needs to have have at least one `TaikoL2.anchor` / `TaikoL2.anchorV2`.
+		return nil, fmt.Errorf("too less transactions in the block")
+	}
+
 	env.header.GasLimit = blkMeta.GasLimit
 
 	// Commit transactions.
@@ -164,6 +218,14 @@ func (w *Miner) sealBlockWith(
 
 	env.gasPool = new(core.GasPool).AddGas(gasLimit)
 
+	// Record the proposed transactions left out of the block, so that it can be
+	// audited why they are missing on L2.
+	var skipped []*rawdb.SkippedTransaction
+	skip := func(tx *types.Transaction, reason string) {
+		log.Debug("Skip a proposed transaction", "hash", tx.Hash(), "reason", reason)
+		skipped = append(skipped, &rawdb.SkippedTransaction{Hash: tx.Hash(), Reason: reason})
+	}
+
 	for i, tx := range txs {
 		if i == 0 {
 			if err := tx.MarkAsAnchor(); err != nil {
@@ -172,19 +234,25 @@ func (w *Miner) sealBlockWith(
 		}
 		// Skip blob transactions
 		if tx.Type() == types.BlobTxType {
-			log.Debug("Skip a blob transaction", "hash", tx.Hash())
+			skip(tx, "blob transaction")
+			continue
+		}
+		// Skip the anchor calls which are not the first transaction, since such
+		// blocks will be rejected by the consensus engine.
+		if i != 0 && w.isAnchorCall(tx) {
+			skip(tx, "unexpected anchor call")
 			continue
 		}
 		sender, err := types.LatestSignerForChainID(w.chainConfig.ChainID).Sender(tx)
 		if err != nil {
-			log.Debug("Skip an invalid proposed transaction", "hash", tx.Hash(), "reason", err)
+			skip(tx, fmt.Sprintf("invalid sender: %v", err))
 			continue
 		}
 
 		env.state.Prepare(rules, sender, blkMeta.Beneficiary, tx.To(), vm.ActivePrecompiles(rules), tx.AccessList())
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 		if err := w.commitTransaction(env, tx); err != nil {
-			log.Debug("Skip an invalid proposed transaction", "hash", tx.Hash(), "reason", err)
+			skip(tx, err.Error())
 			continue
 		}
 		env.tcount++
@@ -207,6 +275,10 @@ func (w *Miner) sealBlockWith(
 	}
 	block = <-results
 
+	if len(skipped) > 0 {
+		w.chain.AddSkippedTransactions(block, skipped)
+	}
+
 	return block, nil
 }
 
@@ -229,23 +301,85 @@ func (w *Miner) getPendingTxs(localAccounts []string, baseFee *big.Int) (
 	return localTxs, remoteTxs
 }
 
-// commitL2Transactions tries to commit the transactions into the given state.
+// dropCommittedTxs returns a copy of the given pending transactions without the
+// ones already committed into the previous transactions lists, so that strategies
+// only order the transactions which are still executable.
+func dropCommittedTxs(
+	env *environment,
+	pending map[common.Address][]*txpool.LazyTransaction,
+) map[common.Address][]*txpool.LazyTransaction {
+	txs := make(map[common.Address][]*txpool.LazyTransaction, len(pending))
+	for from, accTxs := range pending {
+		nonce := env.state.GetNonce(from)
+		for len(accTxs) > 0 {
+			if tx := accTxs[0].Resolve(); tx == nil || tx.Nonce() >= nonce {
+				break
+			}
+			accTxs = accTxs[1:]
+		}
+		if len(accTxs) > 0 {
+			txs[from] = accTxs
+		}
+	}
+	return txs
+}
+
+// l2Transaction is a transaction committed into a transactions list, with the fees
+// it paid in the execution.
+type l2Transaction struct {
+	tx                *types.Transaction
+	sender            common.Address
+	priorityFee       *big.Int
+	baseFeeToCoinbase *big.Int
+	baseFeeToTreasury *big.Int
+}
+
+// newL2Transaction calculates the fees paid by the given executed transaction, the
+// base fee is shared between the coinbase and treasury like in the state transition.
+func (w *Miner) newL2Transaction(env *environment, tx *types.Transaction, sender common.Address, receipt *types.Receipt) *l2Transaction {
+	committed := &l2Transaction{
+		tx:                tx,
+		sender:            sender,
+		priorityFee:       new(big.Int),
+		baseFeeToCoinbase: new(big.Int),
+		baseFeeToTreasury: new(big.Int),
+	}
+	if tip, err := tx.EffectiveGasTip(env.header.BaseFee); err == nil {
+		committed.priorityFee.Mul(new(big.Int).SetUint64(receipt.GasUsed), tip)
+	}
+	if w.chainConfig.Taiko && env.header.BaseFee != nil {
+		basefeeSharingPctg := w.chainConfig.TaikoParamsAt(env.header.Number, env.header.Time).BasefeeSharingPctg(env.header.Extra)
+		committed.baseFeeToTreasury, committed.baseFeeToCoinbase = core.SplitTaikoBaseFee(env.header.BaseFee, receipt.GasUsed, basefeeSharingPctg)
+	}
+	return committed
+}
+
+// commitL2Transactions tries to commit the transactions into the given state, it
+// returns the committed transactions, the last transaction which exceeds the
+// bytes limit if any, and the hashes of the committed bundles. The local
+// transactions always go first, then the bundles are interleaved with the remote
+// transactions by the average tip they pay.
 func (w *Miner) commitL2Transactions(
 	env *environment,
-	firstTransaction *types.Transaction,
-	txsLocal *transactionsByPriceAndNonce,
-	txsRemote *transactionsByPriceAndNonce,
+	firstTransaction *l2Transaction,
+	txsLocal TxOrdering,
+	txsRemote TxOrdering,
+	bundles *l2Bundles,
+	codec TxListCodec,
 	maxBytesPerTxList uint64,
 	minTip uint64,
-) *types.Transaction {
+) (*l2Transaction, []*l2Transaction, []common.Hash) {
 	var (
 		txs             = txsLocal
 		isLocal         = true
-		lastTransaction *types.Transaction
+		lastTransaction *l2Transaction
+		committed       []*l2Transaction
+		landed          []common.Hash
 	)
 
 	if firstTransaction != nil {
-		env.txs = append(env.txs, firstTransaction)
+		env.txs = append(env.txs, firstTransaction.tx)
+		committed = append(committed, firstTransaction)
 	}
 
 loop:
@@ -257,13 +391,32 @@ loop:
 		}
 
 		// Retrieve the next transaction and abort if all done.
-		ltx, _ := txs.Peek()
-		if ltx == nil {
-			if isLocal {
-				txs = txsRemote
-				isLocal = false
-				continue
+		ltx, tip := txs.Peek()
+		if ltx == nil && isLocal {
+			txs = txsRemote
+			isLocal = false
+			continue
+		}
+		// Simulate the next bundle ahead of the remote transaction paying less.
+		if bundle := bundles.peek(); !isLocal && bundle != nil && (ltx == nil || bundle.tip.Cmp(tip) >= 0) {
+			bundleTxs, err := w.commitL2Bundle(env, bundle, codec, maxBytesPerTxList)
+			switch {
+			case errors.Is(err, errBundleTooLarge):
+				log.Trace("Postponing bundle to the next list", "hash", bundle.Hash())
+				bundles.postpone()
+
+			case err != nil:
+				log.Trace("Bundle failed, skipped", "hash", bundle.Hash(), "err", err)
+				bundles.pop()
+
+			default:
+				committed = append(committed, bundleTxs...)
+				landed = append(landed, bundle.Hash())
+				bundles.pop()
 			}
+			continue
+		}
+		if ltx == nil {
 			break
 		}
 		tx := ltx.Resolve()
@@ -274,6 +427,12 @@ loop:
 			continue
 		}
 
+		if w.isAnchorCall(tx) {
+			log.Trace("Ignoring anchor call", "hash", tx.Hash())
+			txs.Pop()
+			continue
+		}
+
 		if tx.GasTipCapIntCmp(new(big.Int).SetUint64(minTip)) < 0 {
 			log.Trace("Ignoring transaction with low tip", "hash", tx.Hash(), "tip", tx.GasTipCap(), "minTip", minTip)
 			txs.Pop()
@@ -305,6 +464,7 @@ loop:
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			txs.Shift()
+			committed = append(committed, w.newL2Transaction(env, tx, from, env.receipts[len(env.receipts)-1]))
 
 			data, err := rlp.EncodeToBytes(env.txs)
 			if err != nil {
@@ -314,15 +474,16 @@ loop:
 			}
 			if len(data) >= int(maxBytesPerTxList) {
 				// Encode and compress the txList, if the byte length is > maxBytesPerTxList, remove the latest tx and break.
-				b, err := compress(data)
+				b, err := codec.Compress(data)
 				if err != nil {
 					log.Trace("Failed to rlp encode and compress the pending transaction %s: %w", tx.Hash(), err)
 					txs.Pop()
 					continue
 				}
 				if len(b) > int(maxBytesPerTxList) {
-					lastTransaction = env.txs[env.tcount-1]
-					env.txs = env.txs[0 : env.tcount-1]
+					lastTransaction = committed[len(committed)-1]
+					env.txs = env.txs[:len(env.txs)-1]
+					committed = committed[:len(committed)-1]
 					break loop
 				}
 			}
@@ -335,32 +496,12 @@ loop:
 		}
 	}
 
-	return lastTransaction
-}
-
-// encodeAndCompressTxList encodes and compresses the given transactions list.
-func encodeAndCompressTxList(txs types.Transactions) ([]byte, error) {
-	b, err := rlp.EncodeToBytes(txs)
-	if err != nil {
-		return nil, err
-	}
-
-	return compress(b)
+	return lastTransaction, committed, landed
 }
 
-// compress compresses the given txList bytes using zlib.
-func compress(txListBytes []byte) ([]byte, error) {
-	var b bytes.Buffer
-	w := zlib.NewWriter(&b)
-	defer w.Close()
-
-	if _, err := w.Write(txListBytes); err != nil {
-		return nil, err
-	}
-
-	if err := w.Flush(); err != nil {
-		return nil, err
-	}
-
-	return b.Bytes(), nil
+// isAnchorCall checks if the given transaction calls the TaikoL2 anchor methods,
+// it always returns false when the Taiko consensus engine is not used.
+func (w *Miner) isAnchorCall(tx *types.Transaction) bool {
+	taikoEngine, ok := w.engine.(*taiko.Taiko)
+	return ok && taikoEngine.IsAnchorCall(tx)
 }
@@ -3,13 +3,20 @@ package miner
 import (
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -60,12 +67,284 @@ func TestBuildTransactionsLists(t *testing.T) {
 	txList, err := w.BuildTransactionsLists(
 		testBankAddress,
 		nil,
+		nil,
 		240_000_000,
 		uint64(maxBytesPerTxList)/10,
 		nil,
 		1,
+		nil,
+		"",
 	)
 	assert.NoError(t, err)
 	assert.LessOrEqual(t, 1, len(txList))
 	assert.LessOrEqual(t, txList[0].BytesLength, uint64(maxBytesPerTxList))
 }
+
+func TestBuildTransactionsListsWithBlobBudget(t *testing.T) {
+	w := testGenerateWorker(t, 2000)
+
+	// Use tiny blobs, so that the transactions lists don't fit in a single one.
+	blobBudget := &BlobBudget{MaxBlobs: 2, FieldElementsPerBlob: 64}
+	txLists, err := w.BuildTransactionsLists(
+		testBankAddress,
+		nil,
+		nil,
+		240_000_000,
+		0,
+		nil,
+		2,
+		blobBudget,
+		"",
+	)
+	assert.NoError(t, err)
+	assert.Len(t, txLists, 2)
+	for _, txList := range txLists {
+		assert.LessOrEqual(t, txList.BytesLength, blobBudget.MaxBytes())
+		assert.Equal(t, (txList.BytesLength+30)/31, txList.FieldElements)
+		assert.Equal(t, (txList.FieldElements+63)/64, txList.BlobCount)
+		assert.LessOrEqual(t, txList.BlobCount, blobBudget.MaxBlobs)
+		assert.Greater(t, txList.BlobCount, uint64(1))
+	}
+
+	_, err = w.BuildTransactionsLists(testBankAddress, nil, nil, 240_000_000, 0, nil, 1, &BlobBudget{}, "")
+	assert.Error(t, err)
+}
+
+func TestBuildTransactionsListsWithOrderingStrategy(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = *params.TestChainConfig
+	)
+	config.Taiko = true
+
+	w, b := newTestWorker(t, &config, ethash.NewFaker(), db, 0)
+	for i := 0; i < 2*defaultSenderCap; i++ {
+		b.txPool.Add([]*types.Transaction{newRandomTx(b.txPool, false)}, true, true)
+	}
+
+	// The sender is capped in every list, the committed transactions are skipped
+	// when ordering the following lists.
+	txLists, err := w.BuildTransactionsLists(testBankAddress, nil, nil, 240_000_000, 131072, nil, 3, nil, TxOrderingSenderCap)
+	assert.NoError(t, err)
+	assert.Len(t, txLists, 3)
+	for i, count := range []uint64{defaultSenderCap, defaultSenderCap, 1} {
+		assert.Equal(t, count, txLists[i].TxCount)
+	}
+
+	_, err = w.BuildTransactionsLists(testBankAddress, nil, nil, 240_000_000, 131072, nil, 1, nil, "unknown")
+	assert.Error(t, err)
+}
+
+func TestBuildTransactionsListsWithBundles(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = *params.TestChainConfig
+	)
+	config.Taiko = true
+
+	w, b := newTestWorker(t, &config, ethash.NewFaker(), db, 0)
+	pool := bundlepool.New(b.chain)
+	t.Cleanup(pool.Close)
+	w.SetBundlePool(pool)
+
+	var (
+		baseFee = big.NewInt(params.InitialBaseFee)
+		signer  = types.LatestSigner(&config)
+	)
+	newTx := func(nonce uint64, tip int64, data []byte) *types.Transaction {
+		tx := &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     nonce,
+			Gas:       100_000,
+			GasFeeCap: new(big.Int).Add(baseFee, big.NewInt(tip)),
+			GasTipCap: big.NewInt(tip),
+			Value:     big.NewInt(tip),
+			Data:      data,
+		}
+		// Contract creations with the given init code, transfers otherwise.
+		if data == nil {
+			tx.To = &testUserAddress
+		}
+		return types.MustSignNewTx(testBankKey, signer, tx)
+	}
+	// PUSH1 0, PUSH1 0, REVERT
+	revertingCode := common.FromHex("0x60006000fd")
+
+	var (
+		// Pays more than the pending transaction with the same nonce, so goes first.
+		landed = &bundlepool.Bundle{Txs: types.Transactions{newTx(0, 3*params.GWei, nil), newTx(1, 3*params.GWei, nil)}}
+
+		// Dropped, since its only transaction reverts without being allowed to.
+		single = &bundlepool.Bundle{Txs: types.Transactions{newTx(2, 5*params.GWei/2, revertingCode)}}
+
+		// Dropped as a whole, since a transaction reverts without being allowed to.
+		reverted = &bundlepool.Bundle{Txs: types.Transactions{newTx(2, 2*params.GWei, nil), newTx(3, 2*params.GWei, revertingCode)}}
+
+		// Lands with the same transactions, since the revert is allowed.
+		allowed = &bundlepool.Bundle{Txs: types.Transactions{newTx(2, params.GWei, nil), newTx(3, params.GWei, revertingCode)}}
+
+		// Dropped, since the nonce is too high.
+		gapped = &bundlepool.Bundle{Txs: types.Transactions{newTx(10, params.GWei, nil)}}
+	)
+	allowed.RevertingTxHashes = []common.Hash{allowed.Txs[1].Hash()}
+	for _, bundle := range []*bundlepool.Bundle{landed, single, reverted, allowed, gapped} {
+		_, err := pool.Add(bundle)
+		assert.NoError(t, err)
+	}
+
+	txLists, err := w.BuildTransactionsLists(testBankAddress, baseFee, nil, 240_000_000, 131072, nil, 1, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, txLists, 1)
+	assert.Equal(t, []common.Hash{landed.Hash(), allowed.Hash()}, txLists[0].Bundles)
+
+	var hashes []common.Hash
+	for _, tx := range txLists[0].TxList {
+		hashes = append(hashes, tx.Hash())
+	}
+	assert.Equal(t, []common.Hash{landed.Txs[0].Hash(), landed.Txs[1].Hash(), allowed.Txs[0].Hash(), allowed.Txs[1].Hash()}, hashes)
+}
+
+func TestSealBlockWithSkippedTransactions(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = *params.AllCliqueProtocolChanges
+	)
+	config.Taiko = true
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+
+	w, b := newTestWorker(t, &config, clique.New(config.Clique, db), db, 0)
+
+	var (
+		signer = types.LatestSigner(&config)
+		// The first transaction is the anchor, which has to be a dynamic fee one.
+		valid = types.MustSignNewTx(testBankKey, signer, &types.DynamicFeeTx{
+			ChainID: config.ChainID, Nonce: 0, To: &testUserAddress, Gas: params.TxGas,
+			GasFeeCap: big.NewInt(params.InitialBaseFee), GasTipCap: common.Big0,
+		})
+		futureNonce = types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce: 5, To: &testUserAddress, Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+		wrongChain = types.MustSignNewTx(testBankKey, types.LatestSignerForChainID(big.NewInt(2)), &types.LegacyTx{
+			Nonce: 1, To: &testUserAddress, Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	)
+	txList, err := rlp.EncodeToBytes(types.Transactions{valid, futureNonce, wrongChain})
+	assert.NoError(t, err)
+
+	block, err := w.SealBlockWith(b.chain.CurrentBlock().Hash(), uint64(time.Now().Unix()), &engine.BlockMetadata{
+		Beneficiary: testBankAddress,
+		GasLimit:    params.GenesisGasLimit,
+		TxList:      txList,
+	}, eip1559.CalcBaseFee(&config, b.chain.CurrentBlock()), nil)
+	assert.NoError(t, err)
+	assert.Len(t, block.Transactions(), 1)
+	assert.Equal(t, valid.Hash(), block.Transactions()[0].Hash())
+
+	// The skipped transactions are stored once the block is inserted.
+	skipped, err := rawdb.ReadSkippedTransactions(db, block.Hash())
+	assert.NoError(t, err)
+	assert.Nil(t, skipped)
+
+	_, err = b.chain.InsertChain(types.Blocks{block})
+	assert.NoError(t, err)
+	skipped, err = rawdb.ReadSkippedTransactions(db, block.Hash())
+	assert.NoError(t, err)
+	assert.Len(t, skipped, 2)
+	assert.Equal(t, futureNonce.Hash(), skipped[0].Hash)
+	assert.Contains(t, skipped[0].Reason, core.ErrNonceTooHigh.Error())
+	assert.Equal(t, wrongChain.Hash(), skipped[1].Hash)
+	assert.Contains(t, skipped[1].Reason, "invalid sender")
+}
+
+// Tests that the txList of the block metadata is the plain RLP encoded transactions
+// list, the compressed txLists are rejected.
+func TestSealBlockWithTxListFormat(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = *params.AllCliqueProtocolChanges
+	)
+	config.Taiko = true
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+
+	w, b := newTestWorker(t, &config, clique.New(config.Clique, db), db, 0)
+
+	anchor := types.MustSignNewTx(testBankKey, types.LatestSigner(&config), &types.DynamicFeeTx{
+		ChainID: config.ChainID, Nonce: 0, To: &testUserAddress, Gas: params.TxGas,
+		GasFeeCap: big.NewInt(params.InitialBaseFee), GasTipCap: common.Big0,
+	})
+	plain, err := rlp.EncodeToBytes(types.Transactions{anchor})
+	assert.NoError(t, err)
+	codec, err := TxListCodecAt(&config, common.Big1, 0)
+	assert.NoError(t, err)
+	compressed, err := EncodeTxList(codec, types.Transactions{anchor})
+	assert.NoError(t, err)
+
+	seal := func(txList []byte) (*types.Block, error) {
+		return w.SealBlockWith(b.chain.CurrentBlock().Hash(), uint64(time.Now().Unix()), &engine.BlockMetadata{
+			Beneficiary: testBankAddress,
+			GasLimit:    params.GenesisGasLimit,
+			TxList:      txList,
+		}, big.NewInt(params.InitialBaseFee), nil)
+	}
+	block, err := seal(plain)
+	assert.NoError(t, err)
+	assert.Equal(t, anchor.Hash(), block.Transactions()[0].Hash())
+
+	// The compressed and the malformed txLists are rejected with the RLP error.
+	for _, txList := range [][]byte{compressed, plain[:len(plain)-1]} {
+		_, err = seal(txList)
+		assert.ErrorContains(t, err, "failed to decode txList: rlp")
+	}
+}
+
+func TestBlobBudget(t *testing.T) {
+	budget := &BlobBudget{MaxBlobs: 3}
+	assert.NoError(t, budget.validate())
+	assert.Equal(t, uint64(3*4096*31), budget.MaxBytes())
+	assert.Equal(t, uint64(0), budget.FieldElements(0))
+	assert.Equal(t, uint64(1), budget.FieldElements(31))
+	assert.Equal(t, uint64(2), budget.FieldElements(32))
+	assert.Equal(t, uint64(1), budget.Blobs(4096*31))
+	assert.Equal(t, uint64(2), budget.Blobs(4096*31+1))
+
+	budget = &BlobBudget{MaxBlobs: 1, BytesPerFieldElement: 32, FieldElementsPerBlob: 2}
+	assert.NoError(t, budget.validate())
+	assert.Equal(t, uint64(64), budget.MaxBytes())
+	assert.Equal(t, uint64(2), budget.Blobs(65))
+
+	assert.Error(t, (&BlobBudget{MaxBlobs: 1, BytesPerFieldElement: 33}).validate())
+}
+
+func TestBuildTransactionsListsEconomics(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = *params.TestChainConfig
+	)
+	config.Taiko = true
+	config.OntakeBlock = common.Big0
+
+	w, b := newTestWorker(t, &config, ethash.NewFaker(), db, 0)
+	for i := 0; i < 100; i++ {
+		b.txPool.Add([]*types.Transaction{newRandomTx(b.txPool, false)}, true, true)
+	}
+	// 75% of the base fee is shared with the coinbase by the proposed block, the
+	// extradata of the last sealed block is ignored.
+	w.SetExtra([]byte{10})
+
+	baseFee := big.NewInt(params.InitialBaseFee)
+	txLists, err := w.BuildTransactionsLists(testBankAddress, baseFee, []byte{75}, 240_000_000, 131072, nil, 1, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, txLists, 1)
+
+	txList := txLists[0]
+	assert.Equal(t, uint64(101), txList.TxCount)
+	assert.Equal(t, uint64(1), txList.SenderCount)
+
+	// The pending transaction of the test worker pays exactly the base fee, the
+	// random transfers pay 10x of it, so their tip is the remaining 9x.
+	baseFees := new(big.Int).Mul(new(big.Int).SetUint64(101*params.TxGas), baseFee)
+	tips := new(big.Int).Mul(new(big.Int).SetUint64(100*params.TxGas), baseFee)
+	assert.Equal(t, new(big.Int).Mul(tips, big.NewInt(9)), txList.TotalPriorityFees)
+	assert.Equal(t, new(big.Int).Div(new(big.Int).Mul(baseFees, big.NewInt(75)), big.NewInt(100)), txList.BaseFeeToCoinbase)
+	assert.Equal(t, new(big.Int).Div(new(big.Int).Mul(baseFees, big.NewInt(25)), big.NewInt(100)), txList.BaseFeeToTreasury)
+}
@@ -358,6 +358,21 @@ type ChainConfig struct {
 	// CHANGE(taiko): Taiko network flag.
 	Taiko       bool     `json:"taiko"`
 	OntakeBlock *big.Int `json:"ontakeBlock,omitempty"` // Ontake switch block (nil = no fork, 0 = already activated)
+
+	// CHANGE(taiko): schedule of the Taiko forks by their lower case names, activated
+	// either by block number or timestamp. The Ontake fork may be scheduled here
+	// instead of by OntakeBlock.
+	TaikoForks map[string]*TaikoForkActivation `json:"taikoForks,omitempty"`
+
+	// CHANGE(taiko): codecs compressing the proposed transactions lists by the lower
+	// case names of the Taiko forks, overriding the codecs of the fork parameters.
+	TxListCodecs map[string]string `json:"txListCodecs,omitempty"`
+
+	// CHANGE(taiko): addresses of the Taiko protocol accounts, see the accessors in
+	// taiko_config.go for their defaults.
+	TaikoL2Address     *common.Address `json:"taikoL2Address,omitempty"`     // TaikoL2 contract (nil = derived from the chain ID)
+	TreasuryAddress    *common.Address `json:"treasuryAddress,omitempty"`    // Base fee treasury (nil = derived from the chain ID)
+	GoldenTouchAddress *common.Address `json:"goldenTouchAddress,omitempty"` // Anchor transactions sender (nil = DefaultGoldenTouchAccount)
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -471,6 +486,10 @@ func (c *ChainConfig) Description() string {
 	if c.VerkleTime != nil {
 		banner += fmt.Sprintf(" - Verkle:                      @%-10v\n", *c.VerkleTime)
 	}
+	// CHANGE(taiko): print the Taiko forks.
+	if c.Taiko {
+		banner += "\n" + c.taikoDescription()
+	}
 	return banner
 }
 
@@ -574,9 +593,9 @@ func (c *ChainConfig) IsVerkle(num *big.Int, time uint64) bool {
 	return c.IsLondon(num) && isTimestampForked(c.VerkleTime, time)
 }
 
-// CHANGE(taiko): IsOntake returns whether num is either equal to the Ontake fork block or greater.
-func (c *ChainConfig) IsOntake(num *big.Int) bool {
-	return isBlockForked(c.OntakeBlock, num)
+// CHANGE(taiko): IsOntake returns whether the Ontake fork is activated at the given block.
+func (c *ChainConfig) IsOntake(num *big.Int, time uint64) bool {
+	return c.IsTaikoFork(TaikoOntake, num, time)
 }
 
 // IsEIP4762 returns whether eip 4762 has been activated at given block.
@@ -674,7 +693,8 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
-	return nil
+	// CHANGE(taiko): check the Taiko forks.
+	return c.checkTaikoForkOrder()
 }
 
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
@@ -745,7 +765,8 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
-	return nil
+	// CHANGE(taiko): check the Taiko forks.
+	return c.checkTaikoCompatible(newcfg, headNumber, headTimestamp)
 }
 
 // BaseFeeChangeDenominator bounds the amount the base fee can change between blocks.
@@ -917,6 +938,9 @@ type Rules struct {
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
 	IsVerkle                                                bool
+
+	// CHANGE(taiko): Taiko forks.
+	IsOntake bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -947,5 +971,6 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsPrague:         isMerge && c.IsPrague(num, timestamp),
 		IsVerkle:         isVerkle,
 		IsEIP4762:        isVerkle,
+		IsOntake:         c.IsOntake(num, timestamp),
 	}
 }
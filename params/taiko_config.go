@@ -2,12 +2,24 @@ package params
 
 import (
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
 func u64(val uint64) *uint64 { return &val }
 
+// TxListCodecZlib is the zlib transactions list codec, which is the codec of all
+// the Taiko forks so far.
+const TxListCodecZlib = "zlib"
+
+// TaikoL2AddressSuffix is the suffix of the protocol addresses derived from the
+// chain ID, e.g. 0x1670010000000000000000000000000000010001 on chain 167001.
+const TaikoL2AddressSuffix = "10001"
+
+// DefaultGoldenTouchAccount is the default sender of the anchor transactions.
+var DefaultGoldenTouchAccount = common.HexToAddress("0x0000777735367b36bC9B61C50022d9D0700dB4Ec")
+
 // Network IDs
 var (
 	TaikoMainnetNetworkID     = big.NewInt(167000)
@@ -22,46 +34,137 @@ var (
 	HeklaNetworkID            = big.NewInt(167009)
 )
 
-var networkIDToChainConfig = map[*big.Int]*ChainConfig{
-	TaikoMainnetNetworkID:      TaikoChainConfig,
-	TaikoInternalL2ANetworkID:  TaikoChainConfig,
-	TaikoInternalL2BNetworkID:  TaikoChainConfig,
-	SnaefellsjokullNetworkID:   TaikoChainConfig,
-	AskjaNetworkID:             TaikoChainConfig,
-	GrimsvotnNetworkID:         TaikoChainConfig,
-	EldfellNetworkID:           TaikoChainConfig,
-	JolnirNetworkID:            TaikoChainConfig,
-	KatlaNetworkID:             TaikoChainConfig,
-	HeklaNetworkID:             TaikoChainConfig,
-	MainnetChainConfig.ChainID: MainnetChainConfig,
-	SepoliaChainConfig.ChainID: SepoliaChainConfig,
-	TestChainConfig.ChainID:    TestChainConfig,
-	NonActivatedConfig.ChainID: NonActivatedConfig,
+// Ontake fork blocks of the Taiko networks.
+var (
+	internalDevnetOntakeBlock = uint64(2)
+	heklaOntakeBlock          = uint64(840_512)
+	mainnetOntakeBlock        = uint64(538_304)
+)
+
+// taikoNetworks is the registry of the known Taiko networks, with the block of their
+// Ontake fork if it's scheduled. The configs are built by newTaikoChainConfig on each
+// access, so they never share any pointer, slice or map between callers.
+var taikoNetworks = map[uint64]*uint64{
+	TaikoMainnetNetworkID.Uint64():     &mainnetOntakeBlock,
+	TaikoInternalL2ANetworkID.Uint64(): &internalDevnetOntakeBlock,
+	TaikoInternalL2BNetworkID.Uint64(): nil,
+	SnaefellsjokullNetworkID.Uint64():  nil,
+	AskjaNetworkID.Uint64():            nil,
+	GrimsvotnNetworkID.Uint64():        nil,
+	EldfellNetworkID.Uint64():          nil,
+	JolnirNetworkID.Uint64():           nil,
+	KatlaNetworkID.Uint64():            nil,
+	HeklaNetworkID.Uint64():            &heklaOntakeBlock,
+}
+
+// newTaikoChainConfig creates the config of a Taiko network, all the Ethereum forks
+// are activated at genesis.
+func newTaikoChainConfig(chainID uint64, ontakeBlock *uint64) *ChainConfig {
+	config := &ChainConfig{
+		ChainID:                       new(big.Int).SetUint64(chainID),
+		HomesteadBlock:                big.NewInt(0),
+		EIP150Block:                   big.NewInt(0),
+		EIP155Block:                   big.NewInt(0),
+		EIP158Block:                   big.NewInt(0),
+		ByzantiumBlock:                big.NewInt(0),
+		ConstantinopleBlock:           big.NewInt(0),
+		PetersburgBlock:               big.NewInt(0),
+		IstanbulBlock:                 big.NewInt(0),
+		BerlinBlock:                   big.NewInt(0),
+		LondonBlock:                   big.NewInt(0),
+		ShanghaiTime:                  u64(0),
+		MergeNetsplitBlock:            nil,
+		TerminalTotalDifficulty:       big.NewInt(0),
+		TerminalTotalDifficultyPassed: true,
+		Taiko:                         true,
+	}
+	if ontakeBlock != nil {
+		config.OntakeBlock = new(big.Int).SetUint64(*ontakeBlock)
+	}
+	return config
+}
+
+// TaikoDevChainConfig returns a new config of the Taiko developer chain, which has
+// the chain ID of the developer mode and activates the Ontake fork at genesis.
+func TaikoDevChainConfig() *ChainConfig {
+	return newTaikoChainConfig(AllDevChainProtocolChanges.ChainID.Uint64(), u64(0))
+}
+
+// TaikoChainConfigByNetworkID returns a new config of the given Taiko network, which
+// the caller is free to modify, and whether the network is known.
+func TaikoChainConfigByNetworkID(networkID uint64) (*ChainConfig, bool) {
+	ontakeBlock, ok := taikoNetworks[networkID]
+	if !ok {
+		return nil, false
+	}
+	return newTaikoChainConfig(networkID, ontakeBlock), true
 }
 
+// NetworkIDToChainConfigOrDefault returns the config of the given network, or the
+// AllEthashProtocolChanges config if the network is unknown. The configs of the
+// Taiko networks are built on each call.
 func NetworkIDToChainConfigOrDefault(networkID *big.Int) *ChainConfig {
-	if config, ok := networkIDToChainConfig[networkID]; ok {
-		return config
+	if networkID.IsUint64() {
+		if config, ok := TaikoChainConfigByNetworkID(networkID.Uint64()); ok {
+			return config
+		}
+	}
+	for _, config := range []*ChainConfig{MainnetChainConfig, SepoliaChainConfig, TestChainConfig, NonActivatedConfig} {
+		if config.ChainID.Cmp(networkID) == 0 {
+			return config
+		}
 	}
 
 	return AllEthashProtocolChanges
 }
 
-var TaikoChainConfig = &ChainConfig{
-	ChainID:                       TaikoInternalL2ANetworkID, // Use Internal Devnet network ID by default.
-	HomesteadBlock:                common.Big0,
-	EIP150Block:                   common.Big0,
-	EIP155Block:                   common.Big0,
-	EIP158Block:                   common.Big0,
-	ByzantiumBlock:                common.Big0,
-	ConstantinopleBlock:           common.Big0,
-	PetersburgBlock:               common.Big0,
-	IstanbulBlock:                 common.Big0,
-	BerlinBlock:                   common.Big0,
-	LondonBlock:                   common.Big0,
-	ShanghaiTime:                  u64(0),
-	MergeNetsplitBlock:            nil,
-	TerminalTotalDifficulty:       common.Big0,
-	TerminalTotalDifficultyPassed: true,
-	Taiko:                         true,
+// TxListCodecAt returns the name of the codec compressing the transactions lists
+// proposed for the given L2 block, which is the codec of the active Taiko fork
+// unless it's overridden in the config.
+func (c *ChainConfig) TxListCodecAt(num *big.Int, time uint64) string {
+	fork := c.TaikoForkAt(num, time)
+	if codec := c.TxListCodecs[strings.ToLower(fork.String())]; codec != "" {
+		return codec
+	}
+	return fork.Params().TxListCodec
+}
+
+// TaikoL2ContractAddress returns the address of the TaikoL2 contract, which is the
+// chain ID followed by TaikoL2AddressSuffix if not set explicitly.
+func (c *ChainConfig) TaikoL2ContractAddress() common.Address {
+	if c.TaikoL2Address != nil {
+		return *c.TaikoL2Address
+	}
+	return c.chainIDAddress()
+}
+
+// TaikoTreasuryAddress returns the address receiving the treasury share of the base
+// fee, which is the chain ID followed by TaikoL2AddressSuffix if not set explicitly.
+func (c *ChainConfig) TaikoTreasuryAddress() common.Address {
+	if c.TreasuryAddress != nil {
+		return *c.TreasuryAddress
+	}
+	return c.chainIDAddress()
+}
+
+// GoldenTouchAccount returns the sender of the anchor transactions, which is the
+// DefaultGoldenTouchAccount if not set explicitly.
+func (c *ChainConfig) GoldenTouchAccount() common.Address {
+	if c.GoldenTouchAddress != nil {
+		return *c.GoldenTouchAddress
+	}
+	return DefaultGoldenTouchAccount
+}
+
+// chainIDAddress returns the protocol address derived from the chain ID, the chain
+// ID is padded with zeros up to TaikoL2AddressSuffix.
+func (c *ChainConfig) chainIDAddress() common.Address {
+	prefix := strings.TrimPrefix(c.ChainID.String(), "0")
+
+	return common.HexToAddress(
+		"0x" +
+			prefix +
+			strings.Repeat("0", common.AddressLength*2-len(prefix)-len(TaikoL2AddressSuffix)) +
+			TaikoL2AddressSuffix,
+	)
 }
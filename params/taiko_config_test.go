@@ -1,88 +1,146 @@
 package params
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestNetworkIDToChainConfigOrDefault(t *testing.T) {
 	tests := []struct {
 		name            string
 		networkID       *big.Int
-		wantChainConfig *ChainConfig
+		wantChainID     *big.Int
+		wantOntakeBlock *big.Int
 	}{
-		{
-			"taikoMainnetNetworkID",
-			TaikoMainnetNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"taikoInternalL2ANetworkId",
-			TaikoInternalL2ANetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"taikoInternalL2BNetworkId",
-			TaikoInternalL2BNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"snaefoll",
-			SnaefellsjokullNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"askja",
-			AskjaNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"grimsvotn",
-			GrimsvotnNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"eldfellNetworkID",
-			EldfellNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"jolnirNetworkID",
-			JolnirNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"katlaNetworkID",
-			KatlaNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"heklaNetworkID",
-			HeklaNetworkID,
-			TaikoChainConfig,
-		},
-		{
-			"mainnet",
-			MainnetChainConfig.ChainID,
-			MainnetChainConfig,
-		},
-		{
-			"sepolia",
-			SepoliaChainConfig.ChainID,
-			SepoliaChainConfig,
-		},
-		{
-			"doesntExist",
-			big.NewInt(89390218390),
-			AllEthashProtocolChanges,
-		},
+		{"taikoMainnetNetworkID", TaikoMainnetNetworkID, TaikoMainnetNetworkID, big.NewInt(538_304)},
+		{"taikoInternalL2ANetworkId", TaikoInternalL2ANetworkID, TaikoInternalL2ANetworkID, big.NewInt(2)},
+		{"taikoInternalL2BNetworkId", TaikoInternalL2BNetworkID, TaikoInternalL2BNetworkID, nil},
+		{"snaefoll", SnaefellsjokullNetworkID, SnaefellsjokullNetworkID, nil},
+		{"askja", AskjaNetworkID, AskjaNetworkID, nil},
+		{"grimsvotn", GrimsvotnNetworkID, GrimsvotnNetworkID, nil},
+		{"eldfellNetworkID", EldfellNetworkID, EldfellNetworkID, nil},
+		{"jolnirNetworkID", JolnirNetworkID, JolnirNetworkID, nil},
+		{"katlaNetworkID", KatlaNetworkID, KatlaNetworkID, nil},
+		{"heklaNetworkID", HeklaNetworkID, HeklaNetworkID, big.NewInt(840_512)},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			config := NetworkIDToChainConfigOrDefault(new(big.Int).Set(tt.networkID))
+			if !config.Taiko || config.ChainID.Cmp(tt.wantChainID) != 0 {
+				t.Fatalf("expected Taiko chain %v, got %v", tt.wantChainID, config.ChainID)
+			}
+			if (config.OntakeBlock == nil) != (tt.wantOntakeBlock == nil) ||
+				(config.OntakeBlock != nil && config.OntakeBlock.Cmp(tt.wantOntakeBlock) != 0) {
+				t.Fatalf("expected Ontake block %v, got %v", tt.wantOntakeBlock, config.OntakeBlock)
+			}
+		})
+	}
+
+	for name, tt := range map[string]struct {
+		networkID       *big.Int
+		wantChainConfig *ChainConfig
+	}{
+		"mainnet":     {big.NewInt(1), MainnetChainConfig},
+		"sepolia":     {big.NewInt(11155111), SepoliaChainConfig},
+		"doesntExist": {big.NewInt(89390218390), AllEthashProtocolChanges},
+	} {
+		t.Run(name, func(t *testing.T) {
 			if config := NetworkIDToChainConfigOrDefault(tt.networkID); config != tt.wantChainConfig {
-				t.Fatalf("expected %v, got %v", config, tt.wantChainConfig)
+				t.Fatalf("expected %v, got %v", tt.wantChainConfig, config)
 			}
 		})
 	}
 }
+
+func TestTaikoChainConfigByNetworkIDCopies(t *testing.T) {
+	config, ok := TaikoChainConfigByNetworkID(HeklaNetworkID.Uint64())
+	if !ok {
+		t.Fatal("hekla config not found")
+	}
+	// Modifying a config, including through its pointers, must not affect the
+	// registry, other networks, nor the shared constants.
+	config.ChainID.SetUint64(1)
+	config.OntakeBlock.SetUint64(1)
+	config.LondonBlock.SetUint64(1)
+	*config.ShanghaiTime = 1
+	config.TaikoForks = map[string]*TaikoForkActivation{"ontake": {Block: big.NewInt(1)}}
+	config.TreasuryAddress = &common.Address{0x01}
+
+	config, _ = TaikoChainConfigByNetworkID(HeklaNetworkID.Uint64())
+	if config.ChainID.Cmp(HeklaNetworkID) != 0 || config.OntakeBlock.Uint64() != heklaOntakeBlock {
+		t.Fatalf("hekla config modified: chain ID %v, Ontake block %v", config.ChainID, config.OntakeBlock)
+	}
+	if config.LondonBlock.Sign() != 0 || *config.ShanghaiTime != 0 || config.TaikoForks != nil || config.TreasuryAddress != nil {
+		t.Fatalf("hekla config modified: %v", config)
+	}
+	if common.Big0.Sign() != 0 {
+		t.Fatalf("shared constant modified: %v", common.Big0)
+	}
+	if mainnet, _ := TaikoChainConfigByNetworkID(TaikoMainnetNetworkID.Uint64()); mainnet.LondonBlock.Sign() != 0 {
+		t.Fatalf("mainnet config modified: %v", mainnet)
+	}
+	if _, ok := TaikoChainConfigByNetworkID(1); ok {
+		t.Fatal("unexpected Taiko config for Ethereum mainnet")
+	}
+}
+
+func TestTxListCodecAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *ChainConfig
+		num       *big.Int
+		wantCodec string
+	}{
+		{"default", &ChainConfig{}, big.NewInt(1), TxListCodecZlib},
+		{"defaultOntake", &ChainConfig{OntakeBlock: big.NewInt(1)}, big.NewInt(1), TxListCodecZlib},
+		{"beforeOntake", &ChainConfig{OntakeBlock: big.NewInt(2), TxListCodecs: map[string]string{"genesis": "a", "ontake": "b"}}, big.NewInt(1), "a"},
+		{"ontake", &ChainConfig{OntakeBlock: big.NewInt(2), TxListCodecs: map[string]string{"genesis": "a", "ontake": "b"}}, big.NewInt(2), "b"},
+		{"ontakeNotOverridden", &ChainConfig{OntakeBlock: big.NewInt(2), TxListCodecs: map[string]string{"genesis": "a"}}, big.NewInt(2), TxListCodecZlib},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if codec := tt.config.TxListCodecAt(tt.num, 0); codec != tt.wantCodec {
+				t.Fatalf("expected %v, got %v", tt.wantCodec, codec)
+			}
+		})
+	}
+}
+
+func TestTaikoAddresses(t *testing.T) {
+	config := &ChainConfig{ChainID: TaikoInternalL2ANetworkID}
+
+	want := common.HexToAddress("0x1670010000000000000000000000000000010001")
+	if addr := config.TaikoL2ContractAddress(); addr != want {
+		t.Fatalf("TaikoL2 address: expected %v, got %v", want, addr)
+	}
+	if addr := config.TaikoTreasuryAddress(); addr != want {
+		t.Fatalf("treasury address: expected %v, got %v", want, addr)
+	}
+	if addr := config.GoldenTouchAccount(); addr != DefaultGoldenTouchAccount {
+		t.Fatalf("golden touch account: expected %v, got %v", DefaultGoldenTouchAccount, addr)
+	}
+
+	// The addresses can be overridden in the genesis JSON.
+	if err := json.Unmarshal([]byte(`{
+		"chainId": 1337,
+		"taikoL2Address": "0x0000000000000000000000000000000000000001",
+		"treasuryAddress": "0x0000000000000000000000000000000000000002",
+		"goldenTouchAddress": "0x0000000000000000000000000000000000000003"
+	}`), config); err != nil {
+		t.Fatal(err)
+	}
+	if addr := config.TaikoL2ContractAddress(); addr != common.BytesToAddress([]byte{1}) {
+		t.Fatalf("TaikoL2 address: expected 0x...01, got %v", addr)
+	}
+	if addr := config.TaikoTreasuryAddress(); addr != common.BytesToAddress([]byte{2}) {
+		t.Fatalf("treasury address: expected 0x...02, got %v", addr)
+	}
+	if addr := config.GoldenTouchAccount(); addr != common.BytesToAddress([]byte{3}) {
+		t.Fatalf("golden touch account: expected 0x...03, got %v", addr)
+	}
+}
@@ -0,0 +1,237 @@
+package params
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// TaikoFork is a fork of the Taiko protocol.
+type TaikoFork int
+
+const (
+	TaikoGenesis TaikoFork = iota // Protocol at genesis, before any fork
+	TaikoOntake
+
+	// LatestTaikoFork is the latest known Taiko fork.
+	LatestTaikoFork = TaikoOntake
+)
+
+// taikoForkNames are the names of the Taiko forks, their lower case versions are the
+// keys of the fork schedule in the chain config.
+var taikoForkNames = [...]string{
+	TaikoGenesis: "Genesis",
+	TaikoOntake:  "Ontake",
+}
+
+// String implements the stringer interface.
+func (f TaikoFork) String() string {
+	if f < TaikoGenesis || f > LatestTaikoFork {
+		return fmt.Sprintf("TaikoFork(%d)", int(f))
+	}
+	return taikoForkNames[f]
+}
+
+// TaikoForkByName returns the Taiko fork with the given case insensitive name.
+func TaikoForkByName(name string) (TaikoFork, bool) {
+	for fork := TaikoGenesis; fork <= LatestTaikoFork; fork++ {
+		if strings.EqualFold(name, fork.String()) {
+			return fork, true
+		}
+	}
+	return 0, false
+}
+
+// TaikoForkActivation schedules a Taiko fork, either by block number or timestamp.
+type TaikoForkActivation struct {
+	Block *big.Int `json:"block,omitempty"` // Activation block (nil = timestamp based)
+	Time  *uint64  `json:"time,omitempty"`  // Activation timestamp (nil = block based)
+}
+
+// block returns the activation block, if the fork is block based.
+func (a *TaikoForkActivation) block() *big.Int {
+	if a == nil {
+		return nil
+	}
+	return a.Block
+}
+
+// time returns the activation timestamp, if the fork is timestamp based.
+func (a *TaikoForkActivation) time() *uint64 {
+	if a == nil {
+		return nil
+	}
+	return a.Time
+}
+
+// active returns whether the fork is activated at the given block.
+func (a *TaikoForkActivation) active(num *big.Int, time uint64) bool {
+	if a == nil {
+		return false
+	}
+	if a.Block != nil {
+		return isBlockForked(a.Block, num)
+	}
+	return isTimestampForked(a.Time, time)
+}
+
+// TaikoForkParams are the protocol parameters which may change with each Taiko fork.
+type TaikoForkParams struct {
+	AnchorMethods   []string           // Signatures of the TaikoL2 methods accepted as the anchor call
+	AnchorGasLimit  uint64             // Gas limit of the anchor transaction
+	MinBaseFee      *big.Int           // Minimum base fee of the L2 blocks (nil = no minimum)
+	DecodeExtraData func([]byte) uint8 // Decodes the base fee sharing percentage from the extradata (nil = not shared)
+	TxListCodec     string             // Codec compressing the proposed transactions lists
+}
+
+// taikoForkParams are the parameters of each Taiko fork.
+var taikoForkParams = [...]*TaikoForkParams{
+	TaikoGenesis: {
+		AnchorMethods:  []string{"anchor(bytes32,bytes32,uint64,uint32)"},
+		AnchorGasLimit: 250_000,
+		TxListCodec:    TxListCodecZlib,
+	},
+	TaikoOntake: {
+		AnchorMethods:   []string{"anchorV2(uint64,bytes32,uint32,(uint8,uint8,uint32,uint64,uint32))"},
+		AnchorGasLimit:  250_000,
+		MinBaseFee:      big.NewInt(8_847_185), // The minimum base fee of TaikoL2 (0.008847185 GWei)
+		DecodeExtraData: decodeOntakeExtraData,
+		TxListCodec:     TxListCodecZlib,
+	},
+}
+
+// Params returns a copy of the protocol parameters of the fork, which the caller is
+// free to modify.
+func (f TaikoFork) Params() *TaikoForkParams {
+	p := *taikoForkParams[f]
+	p.AnchorMethods = slices.Clone(p.AnchorMethods)
+	if p.MinBaseFee != nil {
+		p.MinBaseFee = new(big.Int).Set(p.MinBaseFee)
+	}
+	return &p
+}
+
+// BasefeeSharingPctg returns the percentage of the base fee paid to the coinbase,
+// decoded from the given extradata of a L2 block.
+func (p *TaikoForkParams) BasefeeSharingPctg(extra []byte) uint8 {
+	if p.DecodeExtraData == nil {
+		return 0
+	}
+	return p.DecodeExtraData(extra)
+}
+
+// decodeOntakeExtraData decodes the basefeeSharingPctg config from the extradata of
+// an Ontake block, the corresponding encoding function in the protocol is
+// `LibProposing._encodeGasConfigs`.
+func decodeOntakeExtraData(extra []byte) uint8 {
+	return uint8(new(big.Int).SetBytes(extra).Uint64())
+}
+
+// taikoForkActivation returns the schedule of the given fork, the OntakeBlock field
+// schedules the Ontake fork if set.
+func (c *ChainConfig) taikoForkActivation(fork TaikoFork) *TaikoForkActivation {
+	if fork == TaikoOntake && c.OntakeBlock != nil {
+		return &TaikoForkActivation{Block: c.OntakeBlock}
+	}
+	return c.TaikoForks[strings.ToLower(fork.String())]
+}
+
+// IsTaikoFork returns whether the given Taiko fork is activated at the given block.
+func (c *ChainConfig) IsTaikoFork(fork TaikoFork, num *big.Int, time uint64) bool {
+	return fork == TaikoGenesis || c.taikoForkActivation(fork).active(num, time)
+}
+
+// TaikoForkAt returns the latest Taiko fork activated at the given block.
+func (c *ChainConfig) TaikoForkAt(num *big.Int, time uint64) TaikoFork {
+	for fork := LatestTaikoFork; fork > TaikoGenesis; fork-- {
+		if c.IsTaikoFork(fork, num, time) {
+			return fork
+		}
+	}
+	return TaikoGenesis
+}
+
+// TaikoParamsAt returns the protocol parameters of the Taiko fork activated at the
+// given block.
+func (c *ChainConfig) TaikoParamsAt(num *big.Int, time uint64) *TaikoForkParams {
+	return c.TaikoForkAt(num, time).Params()
+}
+
+// checkTaikoForkOrder checks that the Taiko forks are scheduled in order, and by
+// either block number or timestamp.
+func (c *ChainConfig) checkTaikoForkOrder() error {
+	for name := range c.TaikoForks {
+		if fork, ok := TaikoForkByName(name); !ok || fork == TaikoGenesis || name != strings.ToLower(fork.String()) {
+			return fmt.Errorf("unknown Taiko fork %q", name)
+		}
+	}
+	for name := range c.TxListCodecs {
+		if fork, ok := TaikoForkByName(name); !ok || name != strings.ToLower(fork.String()) {
+			return fmt.Errorf("txList codec of unknown Taiko fork %q", name)
+		}
+	}
+	if c.OntakeBlock != nil && c.TaikoForks["ontake"] != nil {
+		return fmt.Errorf("ontake fork scheduled by both ontakeBlock and taikoForks")
+	}
+	var last *TaikoForkActivation
+	for fork := TaikoOntake; fork <= LatestTaikoFork; fork++ {
+		cur := c.taikoForkActivation(fork)
+		if cur == nil {
+			last = nil
+			continue
+		}
+		if (cur.Block == nil) == (cur.Time == nil) {
+			return fmt.Errorf("taiko fork %v must be scheduled by either block or timestamp", fork)
+		}
+		if fork > TaikoOntake && last == nil {
+			return fmt.Errorf("unsupported Taiko fork ordering: %v enabled, but %v not enabled", fork, fork-1)
+		}
+		if last != nil {
+			switch {
+			case last.Time != nil && cur.Block != nil:
+				return fmt.Errorf("unsupported Taiko fork ordering: %v used timestamp ordering, but %v reverted to block ordering", fork-1, fork)
+			case last.Block != nil && cur.Block != nil && last.Block.Cmp(cur.Block) > 0:
+				return fmt.Errorf("unsupported Taiko fork ordering: %v enabled at block %v, but %v enabled at block %v", fork-1, last.Block, fork, cur.Block)
+			case last.Time != nil && cur.Time != nil && *last.Time > *cur.Time:
+				return fmt.Errorf("unsupported Taiko fork ordering: %v enabled at timestamp %v, but %v enabled at timestamp %v", fork-1, *last.Time, fork, *cur.Time)
+			}
+		}
+		last = cur
+	}
+	return nil
+}
+
+// checkTaikoCompatible checks whether the Taiko forks of the given config can be
+// rescheduled without rewinding the chain from the given head.
+func (c *ChainConfig) checkTaikoCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
+	for fork := TaikoOntake; fork <= LatestTaikoFork; fork++ {
+		var (
+			oldcfg = c.taikoForkActivation(fork)
+			cfg    = newcfg.taikoForkActivation(fork)
+		)
+		if isForkBlockIncompatible(oldcfg.block(), cfg.block(), headNumber) {
+			return newBlockCompatError(fmt.Sprintf("%v fork block", fork), oldcfg.block(), cfg.block())
+		}
+		if isForkTimestampIncompatible(oldcfg.time(), cfg.time(), headTimestamp) {
+			return newTimestampCompatError(fmt.Sprintf("%v fork timestamp", fork), oldcfg.time(), cfg.time())
+		}
+	}
+	return nil
+}
+
+// taikoDescription returns a human-readable description of the Taiko forks.
+func (c *ChainConfig) taikoDescription() string {
+	banner := "Taiko hard forks:\n"
+	for fork := TaikoOntake; fork <= LatestTaikoFork; fork++ {
+		switch activation := c.taikoForkActivation(fork); {
+		case activation == nil:
+			banner += fmt.Sprintf(" - %-28v not scheduled\n", fork.String()+":")
+		case activation.Block != nil:
+			banner += fmt.Sprintf(" - %-28v #%-8v\n", fork.String()+":", activation.Block)
+		case activation.Time != nil:
+			banner += fmt.Sprintf(" - %-28v @%-10v\n", fork.String()+":", *activation.Time)
+		}
+	}
+	return banner
+}
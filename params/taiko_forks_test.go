@@ -0,0 +1,143 @@
+package params
+
+import (
+	"encoding/json"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTaikoForkAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *ChainConfig
+		num      uint64
+		time     uint64
+		wantFork TaikoFork
+	}{
+		{"unscheduled", &ChainConfig{}, 100, 100, TaikoGenesis},
+		{"beforeOntakeBlock", &ChainConfig{OntakeBlock: big.NewInt(10)}, 9, 100, TaikoGenesis},
+		{"ontakeBlock", &ChainConfig{OntakeBlock: big.NewInt(10)}, 10, 0, TaikoOntake},
+		{"beforeOntakeTime", &ChainConfig{TaikoForks: map[string]*TaikoForkActivation{"ontake": {Time: u64(50)}}}, 100, 49, TaikoGenesis},
+		{"ontakeTime", &ChainConfig{TaikoForks: map[string]*TaikoForkActivation{"ontake": {Time: u64(50)}}}, 0, 50, TaikoOntake},
+		{"ontakeScheduledBlock", &ChainConfig{TaikoForks: map[string]*TaikoForkActivation{"ontake": {Block: big.NewInt(10)}}}, 10, 0, TaikoOntake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num := new(big.Int).SetUint64(tt.num)
+			if fork := tt.config.TaikoForkAt(num, tt.time); fork != tt.wantFork {
+				t.Fatalf("expected %v, got %v", tt.wantFork, fork)
+			}
+			if ontake := tt.config.Rules(num, true, tt.time).IsOntake; ontake != (tt.wantFork == TaikoOntake) {
+				t.Fatalf("expected IsOntake rule %v, got %v", tt.wantFork == TaikoOntake, ontake)
+			}
+			if params := tt.config.TaikoParamsAt(num, tt.time); !slices.Equal(params.AnchorMethods, tt.wantFork.Params().AnchorMethods) {
+				t.Fatalf("expected params of %v", tt.wantFork)
+			}
+		})
+	}
+}
+
+func TestTaikoForkParams(t *testing.T) {
+	if pctg := TaikoGenesis.Params().BasefeeSharingPctg([]byte{75}); pctg != 0 {
+		t.Fatalf("expected no base fee sharing before Ontake, got %d", pctg)
+	}
+	if pctg := TaikoOntake.Params().BasefeeSharingPctg([]byte{75}); pctg != 75 {
+		t.Fatalf("expected base fee sharing 75, got %d", pctg)
+	}
+	if TaikoGenesis.Params().MinBaseFee != nil || TaikoOntake.Params().MinBaseFee == nil {
+		t.Fatal("expected a minimum base fee since Ontake only")
+	}
+	// Modifying the parameters must not affect the fork.
+	p := TaikoOntake.Params()
+	p.MinBaseFee.SetUint64(0)
+	p.AnchorMethods[0] = "anchor()"
+	p.AnchorGasLimit = 0
+	if p = TaikoOntake.Params(); p.MinBaseFee.Uint64() != 8_847_185 || p.AnchorMethods[0] == "anchor()" || p.AnchorGasLimit == 0 {
+		t.Fatalf("Ontake params modified: %+v", p)
+	}
+}
+
+func TestTaikoForksJSON(t *testing.T) {
+	var config ChainConfig
+	if err := json.Unmarshal([]byte(`{"chainId": 167001, "taikoForks": {"ontake": {"time": 100}}}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if !config.IsOntake(common.Big0, 100) || config.IsOntake(common.Big0, 99) {
+		t.Fatal("expected Ontake to be activated at timestamp 100")
+	}
+}
+
+func TestCheckTaikoForkOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		forks   map[string]*TaikoForkActivation
+		ontake  *big.Int
+		wantErr bool
+	}{
+		{"none", nil, nil, false},
+		{"ontakeBlock", nil, big.NewInt(1), false},
+		{"ontakeTime", map[string]*TaikoForkActivation{"ontake": {Time: u64(1)}}, nil, false},
+		{"unknown", map[string]*TaikoForkActivation{"unknown": {Time: u64(1)}}, nil, true},
+		{"twice", map[string]*TaikoForkActivation{"ontake": {Time: u64(1)}}, big.NewInt(1), true},
+		{"both", map[string]*TaikoForkActivation{"ontake": {Block: big.NewInt(1), Time: u64(1)}}, nil, true},
+		{"neither", map[string]*TaikoForkActivation{"ontake": {}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := *AllEthashProtocolChanges
+			config.TaikoForks, config.OntakeBlock = tt.forks, tt.ontake
+			if err := config.CheckConfigForkOrder(); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckTxListCodecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		codecs  map[string]string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"forks", map[string]string{"genesis": "a", "ontake": "b"}, false},
+		{"unknown", map[string]string{"unknown": "a"}, true},
+		{"upperCase", map[string]string{"Ontake": "a"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := *AllEthashProtocolChanges
+			config.TxListCodecs = tt.codecs
+			if err := config.CheckConfigForkOrder(); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckTaikoCompatible(t *testing.T) {
+	var (
+		byBlock = &ChainConfig{OntakeBlock: big.NewInt(10)}
+		byTime  = &ChainConfig{TaikoForks: map[string]*TaikoForkActivation{"ontake": {Time: u64(100)}}}
+		later   = &ChainConfig{OntakeBlock: big.NewInt(20)}
+	)
+	// Forks may be rescheduled before the head reaches them.
+	if err := byBlock.checkTaikoCompatible(later, big.NewInt(9), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := byBlock.checkTaikoCompatible(later, big.NewInt(10), 0); err == nil || err.RewindToBlock != 9 {
+		t.Fatalf("expected rewind to block 9, got %v", err)
+	}
+	if err := byTime.checkTaikoCompatible(&ChainConfig{}, big.NewInt(0), 100); err == nil || err.RewindToTime != 99 {
+		t.Fatalf("expected rewind to time 99, got %v", err)
+	}
+	if err := byTime.checkTaikoCompatible(byBlock, big.NewInt(5), 50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
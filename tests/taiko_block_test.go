@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Tests that the blocks of the Taiko networks are verified by the Taiko engine, the
+// fixture has an L2 block sharing the timestamp of its parent, which the beacon
+// engine rejects, and an invalid block without anchor transaction.
+func TestTaikoBlockchain(t *testing.T) {
+	var tests map[string]*BlockTest
+	if err := readJSONFile(filepath.Join("..", "cmd", "evm", "testdata", "taiko", "blocktest.json"), &tests); err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) == 0 {
+		t.Fatal("no Taiko block tests")
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			execBlockTest(t, new(testMatcher), test)
+		})
+	}
+}
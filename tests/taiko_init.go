@@ -0,0 +1,15 @@
+package tests
+
+import "github.com/ethereum/go-ethereum/params"
+
+// Register the Taiko chain configs, named after the Taiko fork activated at genesis,
+// e.g. TaikoOntake.
+func init() {
+	for fork := params.TaikoGenesis; fork <= params.LatestTaikoFork; fork++ {
+		config := params.TaikoDevChainConfig()
+		if fork < params.TaikoOntake {
+			config.OntakeBlock = nil
+		}
+		Forks["Taiko"+fork.String()] = config
+	}
+}